@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/game"
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+const writeTimeout = 10 * time.Second
+
+const roleReplay = "replay"
+
+type wsConn struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsConn) WriteJSON(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.conn.WriteJSON(v)
+}
+
+func (c *wsConn) Close() error {
+	return c.closeWith(websocket.CloseGoingAway, "server shutting down")
+}
+
+func (c *wsConn) closeWith(code int, text string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_ = c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, text),
+		time.Now().Add(time.Second))
+	return c.conn.Close()
+}
+
+type handler struct {
+	ctx      context.Context
+	rooms    *game.RoomManager
+	upgrader websocket.Upgrader
+
+	mu    sync.Mutex
+	conns map[*wsConn]struct{}
+	wg    sync.WaitGroup
+}
+
+func newHandler(ctx context.Context, rooms *game.RoomManager) *handler {
+	return &handler{
+		ctx:   ctx,
+		rooms: rooms,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		conns: make(map[*wsConn]struct{}),
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	roomID := req.URL.Query().Get("room")
+	userID := req.URL.Query().Get("user")
+	gameID := req.URL.Query().Get("game")
+	role := req.URL.Query().Get("role")
+	if role == roleReplay {
+		if gameID == "" || userID == "" {
+			http.Error(w, "game and user are required", http.StatusBadRequest)
+			return
+		}
+	} else if roomID == "" || userID == "" {
+		http.Error(w, "room and user are required", http.StatusBadRequest)
+		return
+	}
+	version, _ := strconv.Atoi(req.URL.Query().Get("v"))
+	ws, err := h.upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		log.Printf("upgrade %s/%s: %v", roomID, userID, err)
+		return
+	}
+	conn := &wsConn{conn: ws}
+	if !h.track(conn) {
+		_ = conn.Close()
+		return
+	}
+	defer h.untrack(conn)
+	if err := protocol.CheckVersion(version); err != nil {
+		h.reject(conn, protocol.CodeUnsupportedVersion, err)
+		return
+	}
+	_ = conn.WriteJSON(protocol.Envelope{
+		Type: protocol.TypeHello,
+		Data: protocol.Hello{Version: protocol.Version, MinVersion: protocol.MinVersion},
+	})
+	if role == roleReplay {
+		h.replay(gameID, userID, req.URL.Query().Get("omniscient") == "1", conn)
+		return
+	}
+	if role == game.ViewerSpectator {
+		room, ok := h.rooms.Get(roomID)
+		if !ok {
+			h.reject(conn, game.ErrorCode(game.ErrRoomNotFound), game.ErrRoomNotFound)
+			return
+		}
+		h.spectate(room, userID, conn)
+		return
+	}
+	room, err := h.openRoom(h.ctx, roomID, userID)
+	if err != nil {
+		h.reject(conn, game.ErrorCode(err), err)
+		return
+	}
+	h.serve(room, userID, conn)
+}
+
+func (h *handler) reject(conn *wsConn, code string, err error) {
+	_ = conn.WriteJSON(protocol.Envelope{
+		Type: protocol.TypeError,
+		Data: protocol.Error{Code: code, Msg: err.Error()},
+	})
+	_ = conn.closeWith(websocket.ClosePolicyViolation, err.Error())
+}
+
+func (h *handler) openRoom(ctx context.Context, roomID, userID string) (*game.Room, error) {
+	if room, ok := h.rooms.FindByUser(userID); ok {
+		if room.RoomID != roomID {
+			return nil, game.ErrUserInRoom
+		}
+		return room, nil
+	}
+	room, err := h.rooms.Join(ctx, roomID, userID)
+	if !errors.Is(err, game.ErrRoomNotFound) {
+		return room, err
+	}
+	room, err = h.rooms.CreateWithID(roomID, userID)
+	if errors.Is(err, game.ErrRoomExists) {
+		return h.rooms.Join(ctx, roomID, userID)
+	}
+	return room, err
+}
+
+func (h *handler) serve(room *game.Room, userID string, conn *wsConn) {
+	defer conn.conn.Close()
+	err := room.Submit(h.ctx, func(r *game.Room) error {
+		return r.AttachConn(userID, conn)
+	})
+	if err != nil {
+		log.Printf("attach %s/%s: %v", room.RoomID, userID, err)
+		return
+	}
+	defer func() {
+		left := false
+		_ = room.Submit(context.Background(), func(r *game.Room) error {
+			left = r.DetachConn(userID, conn) && r.Status == game.StatusWaiting
+			return nil
+		})
+		if left {
+			_ = h.rooms.Leave(context.Background(), userID)
+		}
+	}()
+	for {
+		_, raw, err := conn.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		err = room.SubmitMessage(h.ctx, userID, raw)
+		if errors.Is(err, game.ErrRoomClosed) || errors.Is(err, context.Canceled) {
+			return
+		}
+	}
+}
+
+func (h *handler) spectate(room *game.Room, userID string, conn *wsConn) {
+	defer conn.conn.Close()
+	err := room.Submit(h.ctx, func(r *game.Room) error {
+		r.AddSpectator(userID, conn)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = room.Submit(context.Background(), func(r *game.Room) error {
+			r.RemoveSpectator(conn)
+			return nil
+		})
+	}()
+	for {
+		_, raw, err := conn.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		err = room.Submit(h.ctx, func(r *game.Room) error {
+			r.HandleSpectatorMessage(conn, raw)
+			return nil
+		})
+		if errors.Is(err, game.ErrRoomClosed) || errors.Is(err, context.Canceled) {
+			return
+		}
+	}
+}
+
+func (h *handler) replay(gameID, userID string, omniscient bool, conn *wsConn) {
+	record, ok := h.rooms.Archive.Get(gameID)
+	if !ok {
+		h.reject(conn, game.ErrorCode(game.ErrGameNotRecorded), game.ErrGameNotRecorded)
+		return
+	}
+	defer conn.conn.Close()
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		for {
+			_, raw, err := conn.conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case messages <- raw:
+			case <-h.ctx.Done():
+				return
+			}
+		}
+	}()
+	game.NewReplaySession(record, userID, omniscient, conn).Run(h.ctx, messages)
+}
+
+func (h *handler) track(conn *wsConn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conns == nil {
+		return false
+	}
+	h.conns[conn] = struct{}{}
+	h.wg.Add(1)
+	return true
+}
+
+func (h *handler) untrack(conn *wsConn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+	h.wg.Done()
+}
+
+func (h *handler) closeAll() {
+	h.mu.Lock()
+	conns := h.conns
+	h.conns = nil
+	h.mu.Unlock()
+	for conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+func (h *handler) wait() {
+	h.wg.Wait()
+}
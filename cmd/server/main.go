@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/game"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	ruleset := flag.String("rules", "standard", "ruleset for new rooms: standard, simplified or league")
+	roomTTL := flag.Duration("room-ttl", 30*time.Minute, "idle time before a room is reaped")
+	moveTime := flag.Duration("move-time", 0, "per-move time limit, 0 disables it")
+	overtimes := flag.Int("overtimes", 0, "extra per-move periods each player may use")
+	totalTime := flag.Duration("total-time", 0, "total time bank per player, 0 disables it")
+	increment := flag.Duration("increment", 0, "time added to the bank after each move")
+	grace := flag.Duration("reconnect-grace", game.DefaultReconnectGrace, "how long a disconnected player may stay away before forfeiting")
+	archiveSize := flag.Int("archive-size", 1000, "number of finished games kept for replay")
+	onTimeout := flag.String("on-timeout", game.TimeoutForfeit, "what a per-move timeout does: pass or forfeit")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if _, err := game.LookupRules(*ruleset); err != nil {
+		log.Fatal(err)
+	}
+	rooms := game.NewRoomManager(ctx, *roomTTL)
+	rooms.Ruleset = *ruleset
+	rooms.ReconnectGrace = *grace
+	rooms.Archive = game.NewArchive(*archiveSize)
+	if *moveTime > 0 || *totalTime > 0 {
+		rooms.TimeControl = &game.TimeControl{
+			PerMove:   *moveTime,
+			Overtimes: *overtimes,
+			Total:     *totalTime,
+			Increment: *increment,
+			OnTimeout: *onTimeout,
+		}
+	}
+	go rooms.RunReaper(ctx, time.Minute)
+
+	h := newHandler(ctx, rooms)
+	mux := http.NewServeMux()
+	mux.Handle("/ws", h)
+
+	srv := &http.Server{Addr: *addr, Handler: mux}
+	srv.RegisterOnShutdown(h.closeAll)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("shutdown: %v", err)
+		}
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	h.wait()
+}
@@ -1,25 +0,0 @@
-package game
-
-const (
-	BoardRows = 12
-	BoardCols = 5
-)
-
-const (
-	CampUnknown = "unknown"
-	CampRed     = "red"
-	CampBlue    = "blue"
-)
-
-const (
-	StatusWaiting  = "waiting"
-	StatusPlaying  = "playing"
-	StatusFinished = "finished"
-)
-
-const (
-	PieceFlag     = "军旗"
-	PieceMine     = "地雷"
-	PieceBomb     = "炸弹"
-	PieceEngineer = "工兵"
-)
@@ -0,0 +1,80 @@
+package game
+
+func (r *Room) Surrender(userID string) error {
+	player, err := r.actingPlayer(userID)
+	if err != nil {
+		return err
+	}
+	r.DrawOfferBy = ""
+	r.forfeit(player, "resign")
+	r.record(ActionEntry{Step: r.Step, Action: ActionSurrender, UserID: userID})
+	return nil
+}
+
+func (r *Room) OfferDraw(userID string) error {
+	if _, err := r.actingPlayer(userID); err != nil {
+		return err
+	}
+	if r.DrawOfferBy != "" {
+		return ErrDrawAlreadyOffered
+	}
+	r.DrawOfferBy = userID
+	r.record(ActionEntry{Step: r.Step, Action: ActionOfferDraw, UserID: userID})
+	return nil
+}
+
+func (r *Room) AcceptDraw(userID string) error {
+	if err := r.checkDrawAnswer(userID); err != nil {
+		return err
+	}
+	r.DrawOfferBy = ""
+	r.finish("", "agreed_draw")
+	r.record(ActionEntry{Step: r.Step, Action: ActionAcceptDraw, UserID: userID})
+	return nil
+}
+
+func (r *Room) DeclineDraw(userID string) error {
+	if err := r.checkDrawAnswer(userID); err != nil {
+		return err
+	}
+	r.DrawOfferBy = ""
+	r.record(ActionEntry{Step: r.Step, Action: ActionDeclineDraw, UserID: userID})
+	return nil
+}
+
+func (r *Room) actingPlayer(userID string) (*Player, error) {
+	if r.Status != StatusPlaying {
+		return nil, ErrRoomNotPlaying
+	}
+	player, err := r.playerByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if player.Camp != CampUnknown && player.Camp != r.Turn {
+		return nil, ErrNotYourTurn
+	}
+	return player, nil
+}
+
+func (r *Room) checkDrawAnswer(userID string) error {
+	if r.Status != StatusPlaying {
+		return ErrRoomNotPlaying
+	}
+	if _, err := r.playerByID(userID); err != nil {
+		return err
+	}
+	if r.DrawOfferBy == "" || r.DrawOfferBy == userID {
+		return ErrNoDrawOffer
+	}
+	return nil
+}
+
+func (r *Room) expireDrawOffer() {
+	if r.DrawOfferBy == "" {
+		return
+	}
+	offerer, err := r.playerByID(r.DrawOfferBy)
+	if err != nil || offerer.Camp != r.Turn {
+		r.DrawOfferBy = ""
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+import "testing"
+
+func TestSurrenderBeforeCampsAssigned(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	if err := r.Surrender("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != StatusFinished || r.Reason != "resign" {
+		t.Fatalf("status %s reason %s", r.Status, r.Reason)
+	}
+	if r.WinnerID != "bob" || r.Winner != "" {
+		t.Errorf("winner %q (%q), want bob with no camp", r.WinnerID, r.Winner)
+	}
+	if got := r.gameOverData(); got.Winner == CampUnknown {
+		t.Errorf("game_over winner = %q", got.Winner)
+	}
+}
+
+func TestSurrenderAfterCampsAssigned(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	piece := facePiece(r, true)
+	if err := r.Flip("alice", piece.X, piece.Y); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Surrender("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if r.WinnerID != "alice" || r.Winner != piece.Camp {
+		t.Errorf("winner %q (%q), want alice (%s)", r.WinnerID, r.Winner, piece.Camp)
+	}
+}
+
+func TestDrawHasNoWinner(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	if err := r.OfferDraw("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AcceptDraw("bob"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Winner != "" || r.WinnerID != "" || r.Reason != "agreed_draw" {
+		t.Errorf("winner %q (%q) reason %s", r.WinnerID, r.Winner, r.Reason)
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"context"
+	"time"
+)
+
+type roomCommand struct {
+	fn    func(*Room) error
+	reply chan error
+}
+
+func (r *Room) Run(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(clockTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-r.commands:
+			cmd.reply <- cmd.fn(r)
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+func (r *Room) Submit(ctx context.Context, fn func(*Room) error) error {
+	cmd := roomCommand{fn: fn, reply: make(chan error, 1)}
+	select {
+	case r.commands <- cmd:
+	case <-r.done:
+		return ErrRoomClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-cmd.reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Room) SubmitMessage(ctx context.Context, userID string, raw []byte) error {
+	return r.Submit(ctx, func(room *Room) error {
+		return room.HandleMessage(userID, raw)
+	})
+}
+
+func (r *Room) SubmitFlip(ctx context.Context, userID string, x, y int) error {
+	return r.Submit(ctx, func(room *Room) error {
+		return room.Flip(userID, x, y)
+	})
+}
+
+func (r *Room) SubmitMove(ctx context.Context, userID string, fromX, fromY, toX, toY int) (*BattleResult, error) {
+	var result *BattleResult
+	err := r.Submit(ctx, func(room *Room) error {
+		var err error
+		result, err = room.Move(userID, fromX, fromY, toX, toY)
+		return err
+	})
+	return result, err
+}
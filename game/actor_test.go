@@ -0,0 +1,71 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubmitConcurrentClients(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go r.Run(ctx)
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		flips int
+	)
+	for _, userID := range []string{"alice", "bob", "alice", "bob"} {
+		wg.Add(1)
+		go func(userID string) {
+			defer wg.Done()
+			for y := 0; y < BoardRows; y++ {
+				for x := 0; x < BoardCols; x++ {
+					raw, _ := json.Marshal(map[string]any{"type": "flip", "data": map[string]int{"x": x, "y": y}})
+					if err := r.SubmitMessage(ctx, userID, raw); err == nil {
+						mu.Lock()
+						flips++
+						mu.Unlock()
+					}
+					_ = r.Submit(ctx, func(room *Room) error {
+						_ = room.SyncData(PlayerViewer(userID))
+						return nil
+					})
+				}
+			}
+		}(userID)
+	}
+	wg.Wait()
+
+	var step int
+	var status string
+	if err := r.Submit(ctx, func(room *Room) error {
+		step, status = room.Step, room.Status
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if flips == 0 {
+		t.Fatal("no flip succeeded")
+	}
+	if status == StatusPlaying && step != flips {
+		t.Errorf("step = %d after %d successful flips", step, flips)
+	}
+	cancel()
+	if err := r.Submit(context.Background(), func(*Room) error { return nil }); err != ErrRoomClosed {
+		t.Errorf("Submit after stop = %v, want ErrRoomClosed", err)
+	}
+}
+
+func TestSubmitContextCanceled(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Submit(ctx, func(*Room) error { return nil }); err != context.Canceled {
+		t.Errorf("Submit = %v, want context.Canceled", err)
+	}
+}
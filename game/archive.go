@@ -0,0 +1,70 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+type GameRecord struct {
+	GameID   string
+	RoomID   string
+	Log      *ActionLog
+	Winner   string
+	WinnerID string
+	Reason   string
+	Finished time.Time
+}
+
+type Archive struct {
+	mu      sync.Mutex
+	limit   int
+	order   []string
+	records map[string]*GameRecord
+}
+
+func NewArchive(limit int) *Archive {
+	return &Archive{
+		limit:   limit,
+		records: make(map[string]*GameRecord),
+	}
+}
+
+func (a *Archive) Save(record *GameRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.records[record.GameID]; !ok {
+		a.order = append(a.order, record.GameID)
+	}
+	a.records[record.GameID] = record
+	for a.limit > 0 && len(a.order) > a.limit {
+		delete(a.records, a.order[0])
+		a.order = a.order[1:]
+	}
+}
+
+func (a *Archive) Get(gameID string) (*GameRecord, bool) {
+	if a == nil {
+		return nil, false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	record, ok := a.records[gameID]
+	return record, ok
+}
+
+func (r *Room) archiveGame() {
+	if r.Archive == nil || r.Log == nil {
+		return
+	}
+	log := *r.Log
+	log.Entries = append([]ActionEntry(nil), r.Log.Entries...)
+	r.Archive.Save(&GameRecord{
+		GameID:   log.GameID,
+		RoomID:   r.RoomID,
+		Log:      &log,
+		Winner:   r.Winner,
+		WinnerID: r.WinnerID,
+		Reason:   r.Reason,
+		Finished: r.now(),
+	})
+}
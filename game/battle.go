@@ -3,91 +3,75 @@ package game
 type BattleResult struct {
 	AttackerID       string
 	DefenderID       string
-	AttackerType     string
-	DefenderType     string
+	AttackerType     PieceKind
+	DefenderType     PieceKind
 	AttackerAlive    bool
 	DefenderAlive    bool
 	Result           string
 	ResultingPieceID string
 	Winner           string
 	Reason           string
+	FlagReveals      []FlagReveal
+	Revealed         bool
+}
+
+type FlagReveal struct {
+	PieceID string
+	Camp    string
+	X       int
+	Y       int
 }
 
 func ResolveBattle(attacker, defender *Piece) *BattleResult {
+	return ResolveBattleWith(standardBattleMatrix, attacker, defender)
+}
+
+func ResolveBattleWith(matrix BattleMatrix, attacker, defender *Piece) *BattleResult {
+	outcome := matrix.Outcome(attacker.Type, defender.Type)
 	result := &BattleResult{
 		AttackerID:    attacker.ID,
 		DefenderID:    defender.ID,
 		AttackerType:  attacker.Type,
 		DefenderType:  defender.Type,
-		AttackerAlive: true,
-		DefenderAlive: true,
-		Result:        "",
+		AttackerAlive: outcome != OutcomeDefenderWin && outcome != OutcomeBothDie,
+		DefenderAlive: outcome != OutcomeAttackerWin && outcome != OutcomeBothDie,
+		Result:        string(outcome),
 	}
-	if defender.Type == PieceFlag {
-		defender.Alive = false
-		result.DefenderAlive = false
-		result.Result = "attacker_win"
+	attacker.Alive = result.AttackerAlive
+	defender.Alive = result.DefenderAlive
+	if !result.DefenderAlive && defender.Type.Info().IsFlag {
 		result.Winner = attacker.Camp
 		result.Reason = "flag_captured"
-		return result
 	}
-	if attacker.Type == PieceBomb || defender.Type == PieceBomb {
-		attacker.Alive = false
-		defender.Alive = false
-		result.AttackerAlive = false
-		result.DefenderAlive = false
-		result.Result = "both_die"
-		return result
+	return result
+}
+
+func (r *BattleResult) ApplyEffects(room *Room, attacker, defender *Piece) {
+	if !room.Rules.CommanderRevealsFlag {
+		return
 	}
-	if defender.Type == PieceMine {
-		if attacker.Type == PieceEngineer {
-			defender.Alive = false
-			result.DefenderAlive = false
-			result.Result = "attacker_win"
-			return result
+	for _, dead := range []*Piece{attacker, defender} {
+		if dead.Alive || !dead.Type.Info().RevealsFlag {
+			continue
+		}
+		for _, flag := range room.Pieces {
+			if flag.Alive && flag.Camp == dead.Camp && flag.Type.Info().IsFlag {
+				flag.Exposed = true
+				r.FlagReveals = append(r.FlagReveals, FlagReveal{
+					PieceID: flag.ID,
+					Camp:    flag.Camp,
+					X:       flag.X,
+					Y:       flag.Y,
+				})
+			}
 		}
-		attacker.Alive = false
-		result.AttackerAlive = false
-		result.Result = "defender_win"
-		return result
-	}
-	if attacker.Rank > defender.Rank {
-		defender.Alive = false
-		result.DefenderAlive = false
-		result.Result = "attacker_win"
-		return result
-	}
-	if attacker.Rank < defender.Rank {
-		attacker.Alive = false
-		result.AttackerAlive = false
-		result.Result = "defender_win"
-		return result
 	}
-	attacker.Alive = false
-	defender.Alive = false
-	result.AttackerAlive = false
-	result.DefenderAlive = false
-	result.Result = "both_die"
-	return result
 }
 
 func (r *BattleResult) CheckGameOver(room *Room) {
 	if r.Winner != "" {
-		room.Winner = r.Winner
-		room.Reason = r.Reason
-		room.Status = StatusFinished
-		return
-	}
-	if !room.HasMovablePieces(CampRed) {
-		room.Winner = CampBlue
-		room.Status = StatusFinished
-		room.Reason = "no_movable_pieces"
-		return
-	}
-	if !room.HasMovablePieces(CampBlue) {
-		room.Winner = CampRed
-		room.Status = StatusFinished
-		room.Reason = "no_movable_pieces"
+		room.finish(r.Winner, r.Reason)
 		return
 	}
+	room.checkGameOver()
 }
@@ -1,16 +1,30 @@
 package game
 
-import "fmt"
+const (
+	CellPost         = "post"
+	CellCamp         = "camp"
+	CellHeadquarters = "headquarters"
+)
+
+type Pos struct {
+	X int
+	Y int
+}
 
 type Cell struct {
 	PieceID  string
 	Walkable bool
+	Kind     string
+	Rail     bool
 }
 
 type Board struct {
 	Rows  int
 	Cols  int
 	Cells [][]Cell
+	roads map[Pos][]Pos
+	rails map[Pos][]Pos
+	lines [][]Pos
 }
 
 func NewBoard(rows, cols int) *Board {
@@ -18,10 +32,27 @@ func NewBoard(rows, cols int) *Board {
 	for i := 0; i < rows; i++ {
 		cells[i] = make([]Cell, cols)
 		for j := 0; j < cols; j++ {
-			cells[i][j] = Cell{Walkable: true}
+			cells[i][j] = Cell{Walkable: true, Kind: CellPost}
+		}
+	}
+	b := &Board{
+		Rows:  rows,
+		Cols:  cols,
+		Cells: cells,
+		roads: make(map[Pos][]Pos),
+		rails: make(map[Pos][]Pos),
+	}
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if x+1 < cols {
+				b.addRoad(Pos{x, y}, Pos{x + 1, y})
+			}
+			if y+1 < rows {
+				b.addRoad(Pos{x, y}, Pos{x, y + 1})
+			}
 		}
 	}
-	return &Board{Rows: rows, Cols: cols, Cells: cells}
+	return b
 }
 
 func (b *Board) InBounds(x, y int) bool {
@@ -30,15 +61,180 @@ func (b *Board) InBounds(x, y int) bool {
 
 func (b *Board) GetCell(x, y int) (Cell, error) {
 	if !b.InBounds(x, y) {
-		return Cell{}, fmt.Errorf("out of bounds")
+		return Cell{}, ErrOutOfBounds
 	}
 	return b.Cells[y][x], nil
 }
 
 func (b *Board) SetPiece(x, y int, pieceID string) error {
 	if !b.InBounds(x, y) {
-		return fmt.Errorf("out of bounds")
+		return ErrOutOfBounds
 	}
 	b.Cells[y][x].PieceID = pieceID
 	return nil
 }
+
+func (b *Board) IsCamp(x, y int) bool {
+	return b.InBounds(x, y) && b.Cells[y][x].Kind == CellCamp
+}
+
+func (b *Board) IsHeadquarters(x, y int) bool {
+	return b.InBounds(x, y) && b.Cells[y][x].Kind == CellHeadquarters
+}
+
+func (b *Board) SetupCells() []Pos {
+	var out []Pos
+	for y := 0; y < b.Rows; y++ {
+		for x := 0; x < b.Cols; x++ {
+			if b.Cells[y][x].Walkable && b.Cells[y][x].Kind != CellCamp {
+				out = append(out, Pos{x, y})
+			}
+		}
+	}
+	return out
+}
+
+func (b *Board) Roads(x, y int) []Pos {
+	return b.roads[Pos{x, y}]
+}
+
+func (b *Board) Rails(x, y int) []Pos {
+	return b.rails[Pos{x, y}]
+}
+
+func (b *Board) Neighbors(x, y int) []Pos {
+	seen := make(map[Pos]bool)
+	var out []Pos
+	for _, p := range b.roads[Pos{x, y}] {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	for _, p := range b.rails[Pos{x, y}] {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (b *Board) IsAdjacent(fromX, fromY, toX, toY int) bool {
+	to := Pos{toX, toY}
+	for _, p := range b.Neighbors(fromX, fromY) {
+		if p == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) CanStep(fromX, fromY, toX, toY int) bool {
+	if !b.InBounds(fromX, fromY) || !b.InBounds(toX, toY) {
+		return false
+	}
+	if !b.Cells[toY][toX].Walkable {
+		return false
+	}
+	return b.IsAdjacent(fromX, fromY, toX, toY)
+}
+
+func (b *Board) CanRail(fromX, fromY, toX, toY int, turn bool) bool {
+	if !b.InBounds(fromX, fromY) || !b.InBounds(toX, toY) {
+		return false
+	}
+	from, to := Pos{fromX, fromY}, Pos{toX, toY}
+	if from == to || !b.Cells[fromY][fromX].Rail || !b.Cells[toY][toX].Rail {
+		return false
+	}
+	if turn {
+		return b.railPath(from, to)
+	}
+	for _, line := range b.lines {
+		if b.railStraight(line, from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Board) railStraight(line []Pos, from, to Pos) bool {
+	i, j := -1, -1
+	for k, p := range line {
+		if p == from {
+			i = k
+		}
+		if p == to {
+			j = k
+		}
+	}
+	if i < 0 || j < 0 {
+		return false
+	}
+	if i > j {
+		i, j = j, i
+	}
+	for k := i + 1; k < j; k++ {
+		if b.Cells[line[k].Y][line[k].X].PieceID != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Board) railPath(from, to Pos) bool {
+	visited := map[Pos]bool{from: true}
+	queue := []Pos{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range b.rails[cur] {
+			if visited[next] {
+				continue
+			}
+			if next == to {
+				return true
+			}
+			visited[next] = true
+			if b.Cells[next.Y][next.X].PieceID == "" {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+func (b *Board) addRoad(p, q Pos) {
+	b.roads[p] = appendEdge(b.roads[p], q)
+	b.roads[q] = appendEdge(b.roads[q], p)
+}
+
+func (b *Board) removeRoad(p, q Pos) {
+	b.roads[p] = removeEdge(b.roads[p], q)
+	b.roads[q] = removeEdge(b.roads[q], p)
+}
+
+func (b *Board) addRail(p, q Pos) {
+	b.rails[p] = appendEdge(b.rails[p], q)
+	b.rails[q] = appendEdge(b.rails[q], p)
+}
+
+func appendEdge(edges []Pos, p Pos) []Pos {
+	for _, e := range edges {
+		if e == p {
+			return edges
+		}
+	}
+	return append(edges, p)
+}
+
+func removeEdge(edges []Pos, p Pos) []Pos {
+	out := edges[:0]
+	for _, e := range edges {
+		if e != p {
+			out = append(out, e)
+		}
+	}
+	return out
+}
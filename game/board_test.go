@@ -0,0 +1,82 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStandardBoardRails(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from, to Pos
+		occupied []Pos
+		turn     bool
+		want     bool
+	}{
+		{"open straight run", Pos{0, 1}, Pos{0, 10}, nil, false, true},
+		{"blocked straight run", Pos{0, 1}, Pos{0, 10}, []Pos{{0, 7}}, false, false},
+		{"occupied target", Pos{0, 1}, Pos{0, 10}, []Pos{{0, 10}}, false, true},
+		{"corner without turning", Pos{0, 3}, Pos{2, 1}, nil, false, false},
+		{"corner with turning", Pos{0, 3}, Pos{2, 1}, nil, true, true},
+		{"turning around a blocker", Pos{0, 3}, Pos{1, 1}, []Pos{{0, 1}}, true, true},
+		{"turning stops at occupied cells", Pos{0, 3}, Pos{1, 1}, []Pos{{0, 1}, {2, 1}}, true, false},
+		{"off the rails", Pos{0, 1}, Pos{1, 2}, nil, true, false},
+		{"across the mountains", Pos{1, 5}, Pos{1, 6}, nil, false, false},
+		{"centre rail crossing", Pos{2, 5}, Pos{2, 6}, nil, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewStandardBoard()
+			for i, p := range tc.occupied {
+				_ = b.SetPiece(p.X, p.Y, string(rune('a'+i)))
+			}
+			if got := b.CanRail(tc.from.X, tc.from.Y, tc.to.X, tc.to.Y, tc.turn); got != tc.want {
+				t.Fatalf("CanRail(%v, %v, turn=%v) = %v, want %v", tc.from, tc.to, tc.turn, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStandardBoardSteps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from, to Pos
+		want     bool
+	}{
+		{"road", Pos{0, 0}, Pos{0, 1}, true},
+		{"mountain cut left", Pos{1, 5}, Pos{1, 6}, false},
+		{"mountain cut right", Pos{3, 5}, Pos{3, 6}, false},
+		{"front line road", Pos{0, 5}, Pos{0, 6}, true},
+		{"into camp diagonally", Pos{0, 1}, Pos{1, 2}, true},
+		{"out of camp diagonally", Pos{2, 8}, Pos{3, 9}, true},
+		{"diagonal between posts", Pos{0, 0}, Pos{1, 1}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewStandardBoard()
+			if got := b.CanStep(tc.from.X, tc.from.Y, tc.to.X, tc.to.Y); got != tc.want {
+				t.Fatalf("CanStep(%v, %v) = %v, want %v", tc.from, tc.to, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCampAndHeadquartersRules(t *testing.T) {
+	pieces := map[string]*Piece{
+		"red-01":  {ID: "red-01", Type: PieceCommander, Camp: CampRed, X: 0, Y: 1, Flipped: true, Alive: true},
+		"red-02":  {ID: "red-02", Type: PieceGeneral, Camp: CampRed, X: 1, Y: 11, Flipped: true, Alive: true},
+		"blue-10": {ID: "blue-10", Type: PieceEngineer, Camp: CampBlue, X: 1, Y: 2, Flipped: true, Alive: true},
+	}
+	r := NewRoom("topology",
+		&Player{UserID: "alice", Camp: CampRed},
+		&Player{UserID: "bob", Camp: CampBlue},
+		pieces)
+	r.Start(CampRed)
+	if _, err := r.Move("alice", 0, 1, 1, 2); !errors.Is(err, ErrDefenderInCamp) {
+		t.Fatalf("attack into camp = %v, want %v", err, ErrDefenderInCamp)
+	}
+	if _, err := r.Move("alice", 1, 11, 1, 10); !errors.Is(err, ErrPieceInHeadquarters) {
+		t.Fatalf("move out of headquarters = %v, want %v", err, ErrPieceInHeadquarters)
+	}
+	if r.Step != 0 || r.Turn != CampRed {
+		t.Fatalf("rejected moves changed state: step %d turn %s", r.Step, r.Turn)
+	}
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"time"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+const clockTick = 200 * time.Millisecond
+
+const (
+	TimeoutPass    = "pass"
+	TimeoutForfeit = "forfeit"
+)
+
+type TimeSource interface {
+	Now() time.Time
+}
+
+// TimeControl combines an optional per-move limit with overtime periods and
+// an optional Fischer-style bank. Running out of the bank always forfeits;
+// OnTimeout only decides what happens when a per-move limit expires.
+type TimeControl struct {
+	PerMove   time.Duration
+	Overtimes int
+	Total     time.Duration
+	Increment time.Duration
+	OnTimeout string
+}
+
+type PlayerClock struct {
+	Remaining time.Duration
+	Overtimes int
+}
+
+func (tc *TimeControl) openingLimit() time.Duration {
+	if tc.PerMove > 0 && (tc.Total <= 0 || tc.PerMove < tc.Total) {
+		return tc.PerMove
+	}
+	return tc.Total
+}
+
+func (r *Room) now() time.Time {
+	if r.TimeSource == nil {
+		return time.Now()
+	}
+	return r.TimeSource.Now()
+}
+
+func (r *Room) nowUnix() int64 {
+	return r.now().Unix()
+}
+
+func (r *Room) startClocks() {
+	if r.TimeControl == nil {
+		return
+	}
+	r.clocks = make(map[string]*PlayerClock)
+	for _, camp := range []string{CampRed, CampBlue} {
+		r.clocks[camp] = &PlayerClock{
+			Remaining: r.TimeControl.Total,
+			Overtimes: r.TimeControl.Overtimes,
+		}
+	}
+	r.resetTurnClock()
+}
+
+func (r *Room) resetTurnClock() {
+	if r.TimeControl == nil {
+		return
+	}
+	r.turnStarted = r.now()
+	r.moveDeadline = r.turnStarted.Add(r.TimeControl.PerMove)
+	if r.Turn == CampUnknown {
+		r.moveDeadline = r.turnStarted.Add(r.TimeControl.openingLimit())
+	}
+	r.clockPausedAt = time.Time{}
+	r.syncClockPause()
+}
+
+func (r *Room) chargeClock() {
+	clock := r.clocks[r.Turn]
+	if clock == nil || r.turnStarted.IsZero() || r.TimeControl.Total <= 0 {
+		return
+	}
+	end := r.now()
+	if !r.clockPausedAt.IsZero() {
+		end = r.clockPausedAt
+	}
+	clock.Remaining -= end.Sub(r.turnStarted)
+	if clock.Remaining < 0 {
+		clock.Remaining = 0
+	}
+	clock.Remaining += r.TimeControl.Increment
+}
+
+func (r *Room) CheckClock() bool {
+	changed := false
+	for r.checkClockOnce() {
+		changed = true
+	}
+	return changed
+}
+
+func (r *Room) checkClockOnce() bool {
+	if r.Status != StatusPlaying || r.TimeControl == nil || r.turnStarted.IsZero() || !r.clockPausedAt.IsZero() {
+		return false
+	}
+	if r.Turn == CampUnknown {
+		if r.now().Before(r.moveDeadline) {
+			return false
+		}
+		r.forceFinish(nil, "timeout")
+		return true
+	}
+	clock := r.clocks[r.Turn]
+	if clock == nil {
+		return false
+	}
+	tc := r.TimeControl
+	now := r.now()
+	if tc.Total > 0 && now.Sub(r.turnStarted) >= clock.Remaining {
+		clock.Remaining = 0
+		r.timeout()
+		return true
+	}
+	if tc.PerMove <= 0 || now.Before(r.moveDeadline) {
+		return false
+	}
+	if clock.Overtimes > 0 {
+		clock.Overtimes--
+		r.moveDeadline = r.moveDeadline.Add(tc.PerMove)
+		return true
+	}
+	if tc.OnTimeout == TimeoutPass {
+		r.pass()
+		return true
+	}
+	r.timeout()
+	return true
+}
+
+func (r *Room) timeout() {
+	player, err := r.currentPlayer()
+	if err != nil {
+		return
+	}
+	r.forceFinish(player, "timeout")
+}
+
+func (r *Room) tick() {
+	changed := r.CheckAbandoned()
+	if !changed {
+		changed = r.CheckClock()
+	}
+	if !changed {
+		return
+	}
+	if r.Status == StatusFinished {
+		r.broadcastGameOver()
+		return
+	}
+	r.broadcastSync()
+}
+
+func (r *Room) clockView() *protocol.Clock {
+	if r.TimeControl == nil || r.clocks == nil {
+		return nil
+	}
+	view := &protocol.Clock{
+		Turn:    r.Turn,
+		Players: make(map[string]protocol.PlayerClock),
+	}
+	if !r.turnStarted.IsZero() {
+		deadline := r.moveDeadline
+		if clock := r.clocks[r.Turn]; clock != nil && r.TimeControl.Total > 0 {
+			bank := r.turnStarted.Add(clock.Remaining)
+			if r.TimeControl.PerMove <= 0 || bank.Before(deadline) {
+				deadline = bank
+			}
+		}
+		view.Deadline = deadline.UnixMilli()
+	}
+	for camp, clock := range r.clocks {
+		view.Players[camp] = protocol.PlayerClock{
+			RemainingMs: clock.Remaining.Milliseconds(),
+			Overtimes:   clock.Overtimes,
+		}
+	}
+	return view
+}
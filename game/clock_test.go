@@ -0,0 +1,92 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTimedRoom(t *testing.T, tc TimeControl) (*Room, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	r := NewRoom("timed",
+		&Player{UserID: "alice", Camp: CampUnknown, Online: true, Conn: &testConn{}},
+		&Player{UserID: "bob", Camp: CampUnknown, Online: true, Conn: &testConn{}},
+		nil)
+	r.TimeSource = clock
+	r.TimeControl = &tc
+	if err := r.Deal(1); err != nil {
+		t.Fatal(err)
+	}
+	r.Start(CampUnknown)
+	return r, clock
+}
+
+func TestOpeningFlipIsTimed(t *testing.T) {
+	r, clock := newTimedRoom(t, TimeControl{PerMove: 30 * time.Second, OnTimeout: TimeoutForfeit})
+	clock.advance(29 * time.Second)
+	if r.CheckClock() {
+		t.Fatal("opening timed out early")
+	}
+	clock.advance(time.Hour)
+	if !r.CheckClock() {
+		t.Fatal("opening never timed out")
+	}
+	if r.Status != StatusFinished || r.Reason != "timeout" || r.Winner != "" || r.WinnerID != "" {
+		t.Errorf("status %s reason %s winner %q/%q", r.Status, r.Reason, r.Winner, r.WinnerID)
+	}
+}
+
+func TestOpeningUsesTotalWithoutPerMove(t *testing.T) {
+	r, clock := newTimedRoom(t, TimeControl{Total: time.Minute})
+	if view := r.clockView(); view.Deadline != clock.t.Add(time.Minute).UnixMilli() {
+		t.Errorf("opening deadline = %d", view.Deadline)
+	}
+	clock.advance(time.Minute)
+	if !r.CheckClock() || r.Status != StatusFinished {
+		t.Fatal("opening without a per-move limit never timed out")
+	}
+}
+
+func TestOpeningChargesFirstFlipper(t *testing.T) {
+	r, clock := newTimedRoom(t, TimeControl{PerMove: 30 * time.Second, Total: time.Minute})
+	clock.advance(20 * time.Second)
+	piece := facePiece(r, true)
+	if err := r.Flip("alice", piece.X, piece.Y); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.clocks[piece.Camp].Remaining; got != 40*time.Second {
+		t.Errorf("first flipper has %v left, want 40s", got)
+	}
+	clock.advance(31 * time.Second)
+	if !r.CheckClock() || r.Status != StatusFinished {
+		t.Fatal("second player never timed out")
+	}
+	if r.WinnerID != "alice" || r.Reason != "timeout" {
+		t.Errorf("winner %q reason %s", r.WinnerID, r.Reason)
+	}
+}
+
+func TestOvertimeThenPass(t *testing.T) {
+	r, clock := newTimedRoom(t, TimeControl{PerMove: 10 * time.Second, Overtimes: 1, OnTimeout: TimeoutPass})
+	piece := facePiece(r, true)
+	if err := r.Flip("alice", piece.X, piece.Y); err != nil {
+		t.Fatal(err)
+	}
+	turn := r.Turn
+	clock.advance(10 * time.Second)
+	if !r.CheckClock() || r.Turn != turn || r.clocks[turn].Overtimes != 0 {
+		t.Fatalf("overtime not consumed: turn %s overtimes %d", r.Turn, r.clocks[turn].Overtimes)
+	}
+	clock.advance(10 * time.Second)
+	if !r.CheckClock() || r.Turn == turn || r.Status != StatusPlaying {
+		t.Fatalf("turn not passed: turn %s status %s", r.Turn, r.Status)
+	}
+}
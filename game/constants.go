@@ -16,10 +16,3 @@ const (
 	StatusPlaying  = "playing"
 	StatusFinished = "finished"
 )
-
-const (
-	PieceFlag     = "军旗"
-	PieceMine     = "地雷"
-	PieceBomb     = "炸弹"
-	PieceEngineer = "工兵"
-)
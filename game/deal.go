@@ -0,0 +1,60 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+func NewArmy(camp string) []*Piece {
+	var army []*Piece
+	for _, info := range pieceCatalogue {
+		for i := 0; i < info.Count; i++ {
+			army = append(army, &Piece{
+				ID:    fmt.Sprintf("%s-%02d", camp, len(army)+1),
+				Type:  info.Kind,
+				Camp:  camp,
+				Alive: true,
+			})
+		}
+	}
+	return army
+}
+
+func NewSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+func Deal(board *Board, seed int64) (map[string]*Piece, error) {
+	army := append(NewArmy(CampRed), NewArmy(CampBlue)...)
+	cells := board.SetupCells()
+	if len(cells) < len(army) {
+		return nil, fmt.Errorf("board has %d setup cells for %d pieces", len(cells), len(army))
+	}
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(cells), func(i, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+	pieces := make(map[string]*Piece, len(army))
+	for i, piece := range army {
+		piece.X = cells[i].X
+		piece.Y = cells[i].Y
+		if err := board.SetPiece(piece.X, piece.Y, piece.ID); err != nil {
+			return nil, err
+		}
+		pieces[piece.ID] = piece
+	}
+	return pieces, nil
+}
+
+func (r *Room) Deal(seed int64) error {
+	board := NewStandardBoard()
+	pieces, err := Deal(board, seed)
+	if err != nil {
+		return err
+	}
+	r.Board = board
+	r.Pieces = pieces
+	r.Seed = seed
+	return nil
+}
@@ -0,0 +1,46 @@
+package game
+
+import "github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+
+func (r *Room) DeltaData(viewer Viewer, lastMove *protocol.LastMove, changed []Pos) protocol.Delta {
+	cells := make([]protocol.CellChange, 0, len(changed))
+	for _, pos := range changed {
+		cell, err := r.Board.GetCell(pos.X, pos.Y)
+		if err != nil {
+			continue
+		}
+		change := protocol.CellChange{Pos: []int{pos.X, pos.Y}}
+		if piece := r.Pieces[cell.PieceID]; piece != nil {
+			change.Cell = r.cellView(viewer, piece)
+		}
+		cells = append(cells, change)
+	}
+	return protocol.Delta{
+		Step:     r.Step,
+		Turn:     r.Turn,
+		Cells:    cells,
+		LastMove: lastMove,
+		Clock:    r.clockView(),
+	}
+}
+
+func (r *Room) broadcastDelta(lastMove *protocol.LastMove, changed []Pos) {
+	r.broadcastView(protocol.TypeDelta, func(viewer Viewer) any {
+		return r.DeltaData(viewer, lastMove, changed)
+	})
+}
+
+func (r *Room) sendSync(userID string) {
+	r.sendTo(userID, protocol.TypeSync, r.SyncData(PlayerViewer(userID)))
+}
+
+func battleChanges(from, to Pos, battle *BattleResult) []Pos {
+	changed := []Pos{from, to}
+	if battle == nil {
+		return changed
+	}
+	for _, reveal := range battle.FlagReveals {
+		changed = append(changed, Pos{X: reveal.X, Y: reveal.Y})
+	}
+	return changed
+}
@@ -0,0 +1,70 @@
+package game
+
+import "errors"
+
+type RuleError struct {
+	Code string
+	Msg  string
+}
+
+func (e *RuleError) Error() string {
+	return e.Msg
+}
+
+func newRuleError(code, msg string) *RuleError {
+	return &RuleError{Code: code, Msg: msg}
+}
+
+var (
+	ErrBadPayload            = newRuleError("bad_payload", "malformed message")
+	ErrUnknownMessage        = newRuleError("unknown_message_type", "unknown message type")
+	ErrRoomNotPlaying        = newRuleError("room_not_playing", "room not playing")
+	ErrPlayerNotFound        = newRuleError("player_not_found", "player not found")
+	ErrTurnNotSet            = newRuleError("turn_not_set", "turn camp not set")
+	ErrNotYourTurn           = newRuleError("not_your_turn", "not your turn")
+	ErrOutOfBounds           = newRuleError("out_of_bounds", "out of bounds")
+	ErrNoPieceToFlip         = newRuleError("no_piece_to_flip", "no piece to flip")
+	ErrPieceNotFound         = newRuleError("piece_not_found", "piece not found")
+	ErrPieceAlreadyFlipped   = newRuleError("piece_already_flipped", "piece already flipped")
+	ErrNoPieceToMove         = newRuleError("no_piece_to_move", "no piece to move")
+	ErrPieceNotAvailable     = newRuleError("piece_not_available", "piece not available")
+	ErrPieceNotFlipped       = newRuleError("piece_not_flipped", "piece not flipped")
+	ErrOpponentPiece         = newRuleError("opponent_piece", "cannot move opponent piece")
+	ErrPieceImmobile         = newRuleError("piece_immobile", "piece cannot move")
+	ErrPieceInHeadquarters   = newRuleError("piece_in_headquarters", "piece in headquarters cannot move")
+	ErrInvalidMove           = newRuleError("invalid_move", "invalid move")
+	ErrDefenderNotAvailable  = newRuleError("defender_not_available", "defender not available")
+	ErrDefenderNotFlipped    = newRuleError("defender_not_flipped", "defender not flipped")
+	ErrAttackOwnPiece        = newRuleError("attack_own_piece", "cannot attack own piece")
+	ErrDefenderInCamp        = newRuleError("defender_in_camp", "cannot attack piece in camp")
+	ErrAttackNotAllowed      = newRuleError("attack_not_allowed", "attack not allowed")
+	ErrFlagCaptureNotAllowed = newRuleError("flag_capture_not_allowed", "piece cannot capture flag")
+	ErrFlagGuarded           = newRuleError("flag_guarded", "flag guarded by mines")
+	ErrSetupOffBoard         = newRuleError("setup_off_board", "piece not on board")
+	ErrSetupOutsideHalf      = newRuleError("setup_outside_half", "piece outside own half")
+	ErrSetupInCamp           = newRuleError("setup_in_camp", "piece placed in camp")
+	ErrSetupFlagPlacement    = newRuleError("setup_flag_placement", "flag must be placed in headquarters")
+	ErrSetupMinePlacement    = newRuleError("setup_mine_placement", "mine must be placed in the last two rows")
+	ErrSetupBombPlacement    = newRuleError("setup_bomb_placement", "bomb cannot be placed in the front row")
+	ErrSetupComposition      = newRuleError("setup_composition", "army composition does not match the catalogue")
+	ErrDrawAlreadyOffered    = newRuleError("draw_already_offered", "draw already offered")
+	ErrNoDrawOffer           = newRuleError("no_draw_offer", "no draw offer to answer")
+	ErrStaleState            = newRuleError("stale_state", "action based on stale state")
+	ErrReplayDiverged        = newRuleError("replay_diverged", "replay diverged from the log")
+	ErrGameNotRecorded       = newRuleError("game_not_recorded", "game not recorded")
+	ErrStepOutOfRange        = newRuleError("step_out_of_range", "step out of range")
+	ErrRoomClosed            = newRuleError("room_closed", "room closed")
+	ErrRoomNotFound          = newRuleError("room_not_found", "room not found")
+	ErrRoomExists            = newRuleError("room_exists", "room already exists")
+	ErrRoomFull              = newRuleError("room_full", "room full")
+	ErrUserInRoom            = newRuleError("user_in_room", "user already in a room")
+	ErrUserNotInRoom         = newRuleError("user_not_in_room", "user not in a room")
+)
+
+func ErrorCode(err error) string {
+	var ruleErr *RuleError
+	if errors.As(err, &ruleErr) {
+		return ruleErr.Code
+	}
+	return "internal"
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+type testConn struct {
+	mu   sync.Mutex
+	msgs []protocol.Envelope
+}
+
+func (c *testConn) WriteJSON(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs = append(c.msgs, v.(protocol.Envelope))
+	return nil
+}
+
+func (c *testConn) messages(msgType string) []any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []any
+	for _, msg := range c.msgs {
+		if msg.Type == msgType {
+			out = append(out, msg.Data)
+		}
+	}
+	return out
+}
+
+func newTestRoom(t *testing.T, seed int64) (*Room, *testConn, *testConn) {
+	t.Helper()
+	conn1, conn2 := &testConn{}, &testConn{}
+	r := NewRoom("test",
+		&Player{UserID: "alice", Camp: CampUnknown, Online: true, Conn: conn1},
+		&Player{UserID: "bob", Camp: CampUnknown, Online: true, Conn: conn2},
+		nil)
+	if err := r.Deal(seed); err != nil {
+		t.Fatal(err)
+	}
+	r.Start(CampUnknown)
+	return r, conn1, conn2
+}
+
+func facePiece(r *Room, faceDown bool) *Piece {
+	for y := 0; y < r.Board.Rows; y++ {
+		for x := 0; x < r.Board.Cols; x++ {
+			if piece := r.Pieces[r.Board.Cells[y][x].PieceID]; piece != nil && piece.Flipped != faceDown {
+				return piece
+			}
+		}
+	}
+	return nil
+}
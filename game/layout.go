@@ -0,0 +1,108 @@
+package game
+
+// Rows 0-5 are the blue half and rows 6-11 the red half. P is a post (兵站),
+// C a camp (行营) and H a headquarters (大本营).
+var standardLayout = [BoardRows]string{
+	"PHPHP",
+	"PPPPP",
+	"PCPCP",
+	"PPCPP",
+	"PCPCP",
+	"PPPPP",
+	"PPPPP",
+	"PCPCP",
+	"PPCPP",
+	"PCPCP",
+	"PPPPP",
+	"PHPHP",
+}
+
+var standardRailLines = [][]Pos{
+	railRow(1),
+	railRow(5),
+	railRow(6),
+	railRow(10),
+	railCol(0, 1, 10),
+	railCol(4, 1, 10),
+	railCol(2, 5, 6),
+}
+
+// Columns 1 and 3 are cut off by the mountains between the two front rows.
+var standardMountains = [][2]Pos{
+	{{1, 5}, {1, 6}},
+	{{3, 5}, {3, 6}},
+}
+
+func NewStandardBoard() *Board {
+	b := NewBoard(BoardRows, BoardCols)
+	for y, row := range standardLayout {
+		for x, c := range row {
+			switch c {
+			case 'C':
+				b.Cells[y][x].Kind = CellCamp
+			case 'H':
+				b.Cells[y][x].Kind = CellHeadquarters
+			}
+		}
+	}
+	for y := 0; y < b.Rows; y++ {
+		for x := 0; x < b.Cols; x++ {
+			if b.Cells[y][x].Kind == CellCamp {
+				b.addCampDiagonals(Pos{x, y})
+			}
+		}
+	}
+	for _, m := range standardMountains {
+		b.removeRoad(m[0], m[1])
+	}
+	for _, line := range standardRailLines {
+		for i := 1; i < len(line); i++ {
+			b.addRail(line[i-1], line[i])
+		}
+		for _, p := range line {
+			b.Cells[p.Y][p.X].Rail = true
+		}
+		b.lines = append(b.lines, line)
+	}
+	return b
+}
+
+func HomeCamp(y int) string {
+	if y < BoardRows/2 {
+		return CampBlue
+	}
+	return CampRed
+}
+
+// homeRow counts rows from the camp's own back edge, starting at 0.
+func homeRow(camp string, y int) int {
+	if camp == CampBlue {
+		return y
+	}
+	return BoardRows - 1 - y
+}
+
+func (b *Board) addCampDiagonals(camp Pos) {
+	for _, d := range [][2]int{{-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+		p := Pos{camp.X + d[0], camp.Y + d[1]}
+		if b.InBounds(p.X, p.Y) {
+			b.addRoad(camp, p)
+		}
+	}
+}
+
+func railRow(y int) []Pos {
+	line := make([]Pos, 0, BoardCols)
+	for x := 0; x < BoardCols; x++ {
+		line = append(line, Pos{x, y})
+	}
+	return line
+}
+
+func railCol(x, fromY, toY int) []Pos {
+	line := make([]Pos, 0, toY-fromY+1)
+	for y := fromY; y <= toY; y++ {
+		line = append(line, Pos{x, y})
+	}
+	return line
+}
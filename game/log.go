@@ -0,0 +1,85 @@
+package game
+
+import "time"
+
+const (
+	ActionFlip        = "flip"
+	ActionMove        = "move"
+	ActionSurrender   = "surrender"
+	ActionOfferDraw   = "offer_draw"
+	ActionAcceptDraw  = "accept_draw"
+	ActionDeclineDraw = "decline_draw"
+	ActionPass        = "pass"
+	ActionFinish      = "finish"
+)
+
+type ActionEntry struct {
+	Step   int
+	Action string
+	UserID string
+	Time   time.Time
+	From   Pos
+	To     Pos
+	Battle *BattleResult
+	Reason string
+}
+
+type ActionLog struct {
+	GameID  string
+	Player1 string
+	Player2 string
+	Rules   Rules
+	Seed    int64
+	Started time.Time
+	Entries []ActionEntry
+}
+
+func (l *ActionLog) Upto(step int) *ActionLog {
+	upto := *l
+	upto.Entries = nil
+	for _, entry := range l.Entries {
+		if entry.Step >= step {
+			break
+		}
+		upto.Entries = append(upto.Entries, entry)
+	}
+	return &upto
+}
+
+func (r *Room) newActionLog() *ActionLog {
+	log := &ActionLog{GameID: newRoomID(), Rules: r.Rules, Seed: r.Seed, Started: r.now()}
+	if r.Player1 != nil {
+		log.Player1 = r.Player1.UserID
+	}
+	if r.Player2 != nil {
+		log.Player2 = r.Player2.UserID
+	}
+	return log
+}
+
+func (r *Room) record(entry ActionEntry) {
+	if r.Log != nil {
+		entry.Time = r.now()
+		r.Log.Entries = append(r.Log.Entries, entry)
+	}
+	if r.Status == StatusFinished {
+		r.afterFinish()
+	}
+}
+
+func (r *Room) pass() {
+	if player, err := r.currentPlayer(); err == nil {
+		r.record(ActionEntry{Step: r.Step, Action: ActionPass, UserID: player.UserID})
+	}
+	r.advanceTurn()
+}
+
+func (r *Room) forceFinish(loser *Player, reason string) {
+	if loser == nil {
+		r.finish("", reason)
+		r.record(ActionEntry{Step: r.Step, Action: ActionFinish, Reason: reason})
+		return
+	}
+	r.forfeit(loser, reason)
+	r.record(ActionEntry{Step: r.Step, Action: ActionFinish, UserID: loser.UserID, Reason: reason})
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+type managedRoom struct {
+	room   *Room
+	cancel context.CancelFunc
+}
+
+type RoomManager struct {
+	Ruleset        string
+	TimeControl    *TimeControl
+	ReconnectGrace time.Duration
+	Archive        *Archive
+
+	mu    sync.Mutex
+	ctx   context.Context
+	ttl   time.Duration
+	rooms map[string]*managedRoom
+	users map[string]string
+}
+
+func NewRoomManager(ctx context.Context, ttl time.Duration) *RoomManager {
+	return &RoomManager{
+		ctx:   ctx,
+		ttl:   ttl,
+		rooms: make(map[string]*managedRoom),
+		users: make(map[string]string),
+	}
+}
+
+func (m *RoomManager) Create(userID string) (*Room, error) {
+	return m.CreateWithID(newRoomID(), userID)
+}
+
+func (m *RoomManager) CreateWithID(roomID, userID string) (*Room, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[userID]; ok {
+		return nil, ErrUserInRoom
+	}
+	if _, ok := m.rooms[roomID]; ok {
+		return nil, ErrRoomExists
+	}
+	room := NewRoom(roomID, &Player{UserID: userID, Camp: CampUnknown}, nil, nil)
+	if m.Ruleset != "" {
+		rules, err := LookupRules(m.Ruleset)
+		if err != nil {
+			return nil, err
+		}
+		room.Rules = rules
+	}
+	if m.TimeControl != nil {
+		tc := *m.TimeControl
+		room.TimeControl = &tc
+	}
+	room.ReconnectGrace = m.ReconnectGrace
+	room.Archive = m.Archive
+	room.onFinish = m.release
+	room.lastActive = time.Now()
+	ctx, cancel := context.WithCancel(m.ctx)
+	go room.Run(ctx)
+	m.rooms[roomID] = &managedRoom{room: room, cancel: cancel}
+	m.users[userID] = roomID
+	return room, nil
+}
+
+func (m *RoomManager) Join(ctx context.Context, roomID, userID string) (*Room, error) {
+	m.mu.Lock()
+	managed, ok := m.rooms[roomID]
+	if !ok {
+		m.mu.Unlock()
+		return nil, ErrRoomNotFound
+	}
+	if current, ok := m.users[userID]; ok {
+		m.mu.Unlock()
+		if current == roomID {
+			return managed.room, nil
+		}
+		return nil, ErrUserInRoom
+	}
+	m.users[userID] = roomID
+	m.mu.Unlock()
+
+	finished := false
+	err := managed.room.Submit(ctx, func(r *Room) error {
+		if err := r.join(userID); err != nil {
+			return err
+		}
+		finished = r.Status == StatusFinished
+		return nil
+	})
+	if err != nil || finished {
+		m.mu.Lock()
+		if m.users[userID] == roomID {
+			delete(m.users, userID)
+		}
+		m.mu.Unlock()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return managed.room, nil
+}
+
+func (m *RoomManager) Leave(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	roomID, ok := m.users[userID]
+	if !ok {
+		m.mu.Unlock()
+		return ErrUserNotInRoom
+	}
+	managed := m.rooms[roomID]
+	delete(m.users, userID)
+	m.mu.Unlock()
+
+	empty := false
+	err := managed.room.Submit(ctx, func(r *Room) error {
+		empty = r.leave(userID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if empty {
+		m.remove(roomID)
+	}
+	return nil
+}
+
+func (m *RoomManager) Get(roomID string) (*Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	managed, ok := m.rooms[roomID]
+	if !ok {
+		return nil, false
+	}
+	return managed.room, true
+}
+
+func (m *RoomManager) FindByUser(userID string) (*Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	roomID, ok := m.users[userID]
+	if !ok {
+		return nil, false
+	}
+	managed, ok := m.rooms[roomID]
+	if !ok {
+		return nil, false
+	}
+	return managed.room, true
+}
+
+func (m *RoomManager) Reap(ctx context.Context, now time.Time) int {
+	m.mu.Lock()
+	rooms := make(map[string]*Room, len(m.rooms))
+	for id, managed := range m.rooms {
+		rooms[id] = managed.room
+	}
+	m.mu.Unlock()
+
+	reaped := 0
+	for id, room := range rooms {
+		idle := false
+		err := room.Submit(ctx, func(r *Room) error {
+			idle = r.Status != StatusPlaying && now.Sub(r.lastActive) > m.ttl
+			return nil
+		})
+		if errors.Is(err, ErrRoomClosed) || (err == nil && idle) {
+			m.remove(id)
+			reaped++
+		}
+	}
+	return reaped
+}
+
+func (m *RoomManager) RunReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Reap(ctx, now)
+		}
+	}
+}
+
+func (m *RoomManager) release(r *Room) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, player := range r.players() {
+		if m.users[player.UserID] == r.RoomID {
+			delete(m.users, player.UserID)
+		}
+	}
+}
+
+func (m *RoomManager) remove(roomID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	managed, ok := m.rooms[roomID]
+	if !ok {
+		return
+	}
+	managed.cancel()
+	delete(m.rooms, roomID)
+	for userID, id := range m.users {
+		if id == roomID {
+			delete(m.users, userID)
+		}
+	}
+}
+
+func (r *Room) join(userID string) error {
+	if _, err := r.playerByID(userID); err == nil {
+		return nil
+	}
+	if r.Status != StatusWaiting {
+		return ErrRoomFull
+	}
+	r.lastActive = r.now()
+	player := &Player{UserID: userID, Camp: CampUnknown}
+	switch {
+	case r.Player1 == nil:
+		r.Player1 = player
+	case r.Player2 == nil:
+		r.Player2 = player
+	default:
+		return ErrRoomFull
+	}
+	if r.Player1 == nil || r.Player2 == nil {
+		return nil
+	}
+	if err := r.Deal(NewSeed()); err != nil {
+		return err
+	}
+	r.Start(CampUnknown)
+	return nil
+}
+
+func (r *Room) leave(userID string) bool {
+	player, err := r.playerByID(userID)
+	if err != nil {
+		return r.Player1 == nil && r.Player2 == nil
+	}
+	r.lastActive = r.now()
+	switch r.Status {
+	case StatusPlaying:
+		r.forceFinish(player, "opponent_left")
+		r.broadcastGameOver()
+	case StatusWaiting:
+		if r.Player1 == player {
+			r.Player1 = nil
+		} else {
+			r.Player2 = nil
+		}
+	}
+	return r.Player1 == nil && r.Player2 == nil
+}
+
+func newRoomID() string {
+	buf := make([]byte, 6)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
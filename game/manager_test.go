@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestManagerReleasesUsersOnFinish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewRoomManager(ctx, time.Minute)
+	room, err := m.CreateWithID("r1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Join(ctx, "r1", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateWithID("r2", "alice"); err != ErrUserInRoom {
+		t.Fatalf("CreateWithID while playing = %v, want ErrUserInRoom", err)
+	}
+	if err := room.Submit(ctx, func(r *Room) error { return r.Surrender("alice") }); err != nil {
+		t.Fatal(err)
+	}
+	for _, userID := range []string{"alice", "bob"} {
+		if _, ok := m.FindByUser(userID); ok {
+			t.Errorf("%s still bound to a finished room", userID)
+		}
+	}
+	if _, err := m.CreateWithID("r2", "alice"); err != nil {
+		t.Errorf("CreateWithID after finish = %v", err)
+	}
+	if _, err := m.Join(ctx, "r1", "bob"); err != nil {
+		t.Errorf("rejoining a finished room = %v", err)
+	}
+	if _, ok := m.FindByUser("bob"); ok {
+		t.Error("rejoining a finished room bound the user again")
+	}
+}
+
+func TestManagerReapSkipsPlayingRooms(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewRoomManager(ctx, time.Minute)
+	if _, err := m.CreateWithID("playing", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Join(ctx, "playing", "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.CreateWithID("waiting", "carol"); err != nil {
+		t.Fatal(err)
+	}
+	if n := m.Reap(ctx, time.Now().Add(time.Hour)); n != 1 {
+		t.Errorf("Reap = %d, want 1", n)
+	}
+	if _, ok := m.Get("playing"); !ok {
+		t.Error("playing room was reaped")
+	}
+	if _, ok := m.Get("waiting"); ok {
+		t.Error("idle waiting room was not reaped")
+	}
+}
+
+func TestManagerLeaveWaitingRoom(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewRoomManager(ctx, time.Minute)
+	if _, err := m.CreateWithID("r1", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Leave(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Get("r1"); ok {
+		t.Error("empty waiting room was kept")
+	}
+	if _, ok := m.FindByUser("alice"); ok {
+		t.Error("alice still bound after Leave")
+	}
+}
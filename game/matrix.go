@@ -0,0 +1,119 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type BattleOutcome string
+
+const (
+	OutcomeAttackerWin BattleOutcome = "attacker_win"
+	OutcomeDefenderWin BattleOutcome = "defender_win"
+	OutcomeBothDie     BattleOutcome = "both_die"
+	OutcomeForbidden   BattleOutcome = "forbidden"
+)
+
+type BattlePair struct {
+	Attacker PieceKind
+	Defender PieceKind
+}
+
+type BattleMatrix map[BattlePair]BattleOutcome
+
+func StandardBattleMatrix() BattleMatrix {
+	m := make(BattleMatrix)
+	for _, a := range pieceCatalogue {
+		for _, d := range pieceCatalogue {
+			m[BattlePair{a.Kind, d.Kind}] = standardOutcome(a, d)
+		}
+	}
+	return m
+}
+
+func standardOutcome(a, d PieceInfo) BattleOutcome {
+	switch {
+	case a.Immobile:
+		return OutcomeForbidden
+	case d.IsFlag:
+		return OutcomeAttackerWin
+	case a.DestroysAll || d.DestroysAll:
+		return OutcomeBothDie
+	case d.IsMine && a.DefusesMine:
+		return OutcomeAttackerWin
+	case d.IsMine:
+		return OutcomeDefenderWin
+	case a.Rank > d.Rank:
+		return OutcomeAttackerWin
+	case a.Rank < d.Rank:
+		return OutcomeDefenderWin
+	}
+	return OutcomeBothDie
+}
+
+func (m BattleMatrix) With(attacker, defender PieceKind, outcome BattleOutcome) BattleMatrix {
+	out := make(BattleMatrix, len(m))
+	for pair, o := range m {
+		out[pair] = o
+	}
+	out[BattlePair{attacker, defender}] = outcome
+	return out
+}
+
+func (m BattleMatrix) Outcome(attacker, defender PieceKind) BattleOutcome {
+	if outcome, ok := m[BattlePair{attacker, defender}]; ok {
+		return outcome
+	}
+	return OutcomeForbidden
+}
+
+type battleRule struct {
+	Attacker PieceKind
+	Defender PieceKind
+	Outcome  BattleOutcome
+}
+
+func (m BattleMatrix) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	rules := make([]battleRule, 0, len(m))
+	for pair, outcome := range m {
+		rules = append(rules, battleRule{pair.Attacker, pair.Defender, outcome})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Attacker != rules[j].Attacker {
+			return rules[i].Attacker < rules[j].Attacker
+		}
+		return rules[i].Defender < rules[j].Defender
+	})
+	return json.Marshal(rules)
+}
+
+func (m *BattleMatrix) UnmarshalJSON(data []byte) error {
+	var rules []battleRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	if rules == nil {
+		*m = nil
+		return nil
+	}
+	*m = make(BattleMatrix, len(rules))
+	for _, rule := range rules {
+		(*m)[BattlePair{rule.Attacker, rule.Defender}] = rule.Outcome
+	}
+	return nil
+}
+
+func (m BattleMatrix) Validate() error {
+	for _, a := range pieceCatalogue {
+		for _, d := range pieceCatalogue {
+			if _, ok := m[BattlePair{a.Kind, d.Kind}]; !ok {
+				return fmt.Errorf("battle matrix missing %s vs %s", a.Kind, d.Kind)
+			}
+		}
+	}
+	return nil
+}
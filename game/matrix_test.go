@@ -0,0 +1,66 @@
+package game
+
+import "testing"
+
+func TestBattleMatrix(t *testing.T) {
+	tests := []struct {
+		ruleset  string
+		attacker PieceKind
+		defender PieceKind
+		want     BattleOutcome
+	}{
+		{"standard", PieceBomb, PieceFlag, OutcomeAttackerWin},
+		{"standard", PieceBomb, PieceMine, OutcomeBothDie},
+		{"standard", PieceBomb, PieceCommander, OutcomeBothDie},
+		{"standard", PieceCommander, PieceBomb, OutcomeBothDie},
+		{"standard", PieceEngineer, PieceMine, OutcomeAttackerWin},
+		{"standard", PieceCommander, PieceMine, OutcomeDefenderWin},
+		{"standard", PieceCommander, PieceGeneral, OutcomeAttackerWin},
+		{"standard", PieceGeneral, PieceCommander, OutcomeDefenderWin},
+		{"standard", PieceEngineer, PieceEngineer, OutcomeBothDie},
+		{"standard", PieceMine, PieceEngineer, OutcomeForbidden},
+		{"standard", PieceFlag, PieceEngineer, OutcomeForbidden},
+		{"league", PieceBomb, PieceFlag, OutcomeForbidden},
+		{"league", PieceBomb, PieceMine, OutcomeDefenderWin},
+		{"league", PieceBomb, PieceCommander, OutcomeBothDie},
+		{"league", PieceEngineer, PieceMine, OutcomeAttackerWin},
+		{"league", PiecePlatoon, PieceFlag, OutcomeAttackerWin},
+		{"simplified", PieceBomb, PieceFlag, OutcomeAttackerWin},
+		{"simplified", PieceBomb, PieceMine, OutcomeBothDie},
+	}
+	for _, tt := range tests {
+		rules, err := LookupRules(tt.ruleset)
+		if err != nil {
+			t.Fatalf("LookupRules(%q): %v", tt.ruleset, err)
+		}
+		if got := rules.battleMatrix().Outcome(tt.attacker, tt.defender); got != tt.want {
+			t.Errorf("%s: %s vs %s = %s, want %s", tt.ruleset, tt.attacker, tt.defender, got, tt.want)
+		}
+	}
+}
+
+func TestBattleMatrixComplete(t *testing.T) {
+	for name := range rulesets {
+		rules, err := LookupRules(name)
+		if err != nil {
+			t.Errorf("LookupRules(%q): %v", name, err)
+			continue
+		}
+		if err := rules.Battle.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := LookupRules("nope"); err == nil {
+		t.Error("LookupRules(nope) succeeded")
+	}
+}
+
+func TestResolveBattleLeague(t *testing.T) {
+	rules := LeagueRules()
+	bomb := &Piece{ID: "red-01", Type: PieceBomb, Camp: CampRed, Alive: true}
+	mine := &Piece{ID: "blue-01", Type: PieceMine, Camp: CampBlue, Alive: true}
+	result := ResolveBattleWith(rules.battleMatrix(), bomb, mine)
+	if result.AttackerAlive || !result.DefenderAlive {
+		t.Errorf("bomb vs mine: attacker alive %v, defender alive %v", result.AttackerAlive, result.DefenderAlive)
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import "fmt"
+
+type PieceKind int
+
+const (
+	PieceUnknown PieceKind = iota
+	PieceCommander
+	PieceGeneral
+	PieceDivision
+	PieceBrigade
+	PieceRegiment
+	PieceBattalion
+	PieceCompany
+	PiecePlatoon
+	PieceEngineer
+	PieceBomb
+	PieceMine
+	PieceFlag
+)
+
+type PieceInfo struct {
+	Kind        PieceKind
+	NameZh      string
+	NameEn      string
+	Rank        int
+	Count       int
+	Immobile    bool
+	DestroysAll bool
+	DefusesMine bool
+	RailTurns   bool
+	IsMine      bool
+	IsFlag      bool
+	RevealsFlag bool
+}
+
+var pieceCatalogue = []PieceInfo{
+	{Kind: PieceCommander, NameZh: "司令", NameEn: "commander", Rank: 9, Count: 1, RevealsFlag: true},
+	{Kind: PieceGeneral, NameZh: "军长", NameEn: "general", Rank: 8, Count: 1},
+	{Kind: PieceDivision, NameZh: "师长", NameEn: "division", Rank: 7, Count: 2},
+	{Kind: PieceBrigade, NameZh: "旅长", NameEn: "brigade", Rank: 6, Count: 2},
+	{Kind: PieceRegiment, NameZh: "团长", NameEn: "regiment", Rank: 5, Count: 2},
+	{Kind: PieceBattalion, NameZh: "营长", NameEn: "battalion", Rank: 4, Count: 2},
+	{Kind: PieceCompany, NameZh: "连长", NameEn: "company", Rank: 3, Count: 3},
+	{Kind: PiecePlatoon, NameZh: "排长", NameEn: "platoon", Rank: 2, Count: 3},
+	{Kind: PieceEngineer, NameZh: "工兵", NameEn: "engineer", Rank: 1, Count: 3, DefusesMine: true, RailTurns: true},
+	{Kind: PieceBomb, NameZh: "炸弹", NameEn: "bomb", Count: 2, DestroysAll: true},
+	{Kind: PieceMine, NameZh: "地雷", NameEn: "mine", Count: 3, Immobile: true, IsMine: true},
+	{Kind: PieceFlag, NameZh: "军旗", NameEn: "flag", Count: 1, Immobile: true, IsFlag: true},
+}
+
+func Catalogue() []PieceInfo {
+	out := make([]PieceInfo, len(pieceCatalogue))
+	copy(out, pieceCatalogue)
+	return out
+}
+
+func (k PieceKind) Info() PieceInfo {
+	for _, info := range pieceCatalogue {
+		if info.Kind == k {
+			return info
+		}
+	}
+	return PieceInfo{Kind: PieceUnknown, Immobile: true}
+}
+
+func ParsePieceKind(name string) (PieceKind, error) {
+	for _, info := range pieceCatalogue {
+		if info.NameZh == name || info.NameEn == name {
+			return info.Kind, nil
+		}
+	}
+	return PieceUnknown, fmt.Errorf("unknown piece kind %q", name)
+}
+
+func (k PieceKind) String() string {
+	if info := k.Info(); info.Kind != PieceUnknown {
+		return info.NameZh
+	}
+	return "unknown"
+}
+
+func (k PieceKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *PieceKind) UnmarshalText(text []byte) error {
+	kind, err := ParsePieceKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+const minPlayInterval = 50 * time.Millisecond
+
+type ReplaySession struct {
+	record *GameRecord
+	viewer Viewer
+	conn   WebSocketConn
+	room   *Room
+	step   int
+	last   int
+	ticker *time.Ticker
+}
+
+func NewReplaySession(record *GameRecord, userID string, omniscient bool, conn WebSocketConn) *ReplaySession {
+	viewer := SpectatorViewer(userID)
+	if userID == record.Log.Player1 || userID == record.Log.Player2 {
+		viewer = PlayerViewer(userID)
+	}
+	if omniscient {
+		viewer = AdminViewer()
+	}
+	last := 0
+	if n := len(record.Log.Entries); n > 0 {
+		last = record.Log.Entries[n-1].Step + 1
+	}
+	return &ReplaySession{record: record, viewer: viewer, conn: conn, last: last}
+}
+
+func (s *ReplaySession) Run(ctx context.Context, messages <-chan []byte) {
+	defer s.pause()
+	s.send(protocol.TypeReplayInfo, protocol.ReplayInfo{
+		GameID:   s.record.GameID,
+		Steps:    s.last,
+		Winner:   s.record.Winner,
+		WinnerID: s.record.WinnerID,
+		Reason:   s.record.Reason,
+	})
+	if err := s.seek(0); err != nil {
+		s.sendError("", err)
+		return
+	}
+	for {
+		var tick <-chan time.Time
+		if s.ticker != nil {
+			tick = s.ticker.C
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case raw, ok := <-messages:
+			if !ok {
+				return
+			}
+			s.handle(raw)
+		case <-tick:
+			if err := s.next(); err != nil || s.step >= s.last {
+				s.pause()
+			}
+		}
+	}
+}
+
+func (s *ReplaySession) handle(raw []byte) {
+	var msg protocol.Message
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		s.sendError("", ErrBadPayload)
+		return
+	}
+	var err error
+	switch msg.Type {
+	case protocol.TypeSeek:
+		var payload protocol.SeekPayload
+		if json.Unmarshal(msg.Data, &payload) != nil {
+			err = ErrBadPayload
+			break
+		}
+		s.pause()
+		err = s.seek(payload.Step)
+	case protocol.TypeNext:
+		s.pause()
+		err = s.next()
+	case protocol.TypePrev:
+		s.pause()
+		err = s.seek(s.step - 1)
+	case protocol.TypePlay:
+		var payload protocol.PlayPayload
+		if len(msg.Data) > 0 && json.Unmarshal(msg.Data, &payload) != nil {
+			err = ErrBadPayload
+			break
+		}
+		s.play(payload.Speed)
+	case protocol.TypePause:
+		s.pause()
+	case protocol.TypePing:
+		s.send(protocol.TypePong, protocol.Pong{TS: time.Now().Unix()})
+	default:
+		err = ErrUnknownMessage
+	}
+	if err != nil {
+		s.sendError(msg.Type, err)
+	}
+}
+
+func (s *ReplaySession) seek(step int) error {
+	if step < 0 || step > s.last {
+		return ErrStepOutOfRange
+	}
+	room, err := Replay(s.record.Log.Seed, s.record.Log.Upto(step))
+	if err != nil {
+		return err
+	}
+	s.room = room
+	s.step = step
+	s.sendState()
+	return nil
+}
+
+func (s *ReplaySession) next() error {
+	if s.step >= s.last {
+		return ErrStepOutOfRange
+	}
+	for _, entry := range s.record.Log.Entries {
+		if entry.Step != s.step {
+			continue
+		}
+		battle, err := s.room.replayEntry(entry)
+		if err != nil {
+			return err
+		}
+		if battle != nil {
+			s.send(protocol.TypeBattle, s.room.battleMessage(s.viewer, entry.From, entry.To, battle))
+		}
+	}
+	s.step++
+	s.sendState()
+	return nil
+}
+
+func (s *ReplaySession) play(speed float64) {
+	if speed <= 0 {
+		speed = 1
+	}
+	interval := time.Duration(float64(time.Second) / speed)
+	if interval < minPlayInterval {
+		interval = minPlayInterval
+	}
+	s.pause()
+	s.ticker = time.NewTicker(interval)
+}
+
+func (s *ReplaySession) pause() {
+	if s.ticker != nil {
+		s.ticker.Stop()
+		s.ticker = nil
+	}
+}
+
+func (s *ReplaySession) sendState() {
+	s.send(protocol.TypeSync, s.room.SyncData(s.viewer))
+	if s.step == s.last && s.room.Status == StatusFinished {
+		gameOver := s.room.gameOverData()
+		gameOver.GameID = s.record.GameID
+		s.send(protocol.TypeGameOver, gameOver)
+	}
+}
+
+func (s *ReplaySession) send(msgType string, data any) {
+	_ = s.conn.WriteJSON(protocol.Envelope{Type: msgType, Data: data})
+}
+
+func (s *ReplaySession) sendError(action string, err error) {
+	s.send(protocol.TypeError, protocol.Error{
+		Code:   ErrorCode(err),
+		Msg:    err.Error(),
+		Action: action,
+	})
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+func archivedBlindGame(t *testing.T) (*Room, *GameRecord) {
+	t.Helper()
+	r, _, _ := newTestRoom(t, 2)
+	r.Rules = customRules()
+	r.Archive = NewArchive(10)
+	r.Log = r.newActionLog()
+	playRandom(t, r, 2, 3000)
+	if r.Status == StatusPlaying {
+		player, err := r.currentPlayer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Surrender(player.UserID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blindAttacks(r.Log) == 0 {
+		t.Fatal("game has no blind attack")
+	}
+	record, ok := r.Archive.Get(r.Log.GameID)
+	if !ok {
+		t.Fatal("finished game was not archived")
+	}
+	return r, record
+}
+
+func runSession(t *testing.T, session *ReplaySession, msgs ...string) {
+	t.Helper()
+	in := make(chan []byte, len(msgs))
+	for _, msg := range msgs {
+		in <- []byte(msg)
+	}
+	close(in)
+	session.Run(context.Background(), in)
+}
+
+func TestPlaybackBlindAttackGame(t *testing.T) {
+	r, record := archivedBlindGame(t)
+	blindStep := -1
+	for _, entry := range record.Log.Entries {
+		if entry.Battle != nil && entry.Battle.Revealed {
+			blindStep = entry.Step
+			break
+		}
+	}
+
+	conn := &testConn{}
+	session := NewReplaySession(record, "carol", true, conn)
+	runSession(t, session,
+		`{"type":"seek","data":{"step":`+itoa(blindStep)+`}}`,
+		`{"type":"next"}`,
+		`{"type":"seek","data":{"step":`+itoa(session.last)+`}}`,
+	)
+	if errs := conn.messages(protocol.TypeError); len(errs) != 0 {
+		t.Fatalf("replay errors: %+v", errs)
+	}
+	info := conn.messages(protocol.TypeReplayInfo)
+	if len(info) != 1 || info[0].(protocol.ReplayInfo).Steps != session.last {
+		t.Fatalf("replay_info = %+v", info)
+	}
+	battles := conn.messages(protocol.TypeBattle)
+	if len(battles) != 1 || !battles[0].(protocol.Battle).Revealed {
+		t.Fatalf("battle messages = %+v", battles)
+	}
+	syncs := conn.messages(protocol.TypeSync)
+	if len(syncs) != 4 {
+		t.Fatalf("got %d syncs, want 4", len(syncs))
+	}
+	want, _ := json.Marshal(r.SyncData(AdminViewer()))
+	got, _ := json.Marshal(syncs[len(syncs)-1])
+	if string(got) != string(want) {
+		t.Error("final replay position differs from the finished game")
+	}
+	overs := conn.messages(protocol.TypeGameOver)
+	if len(overs) != 1 || overs[0].(protocol.GameOver).Reason != r.Reason {
+		t.Errorf("game_over messages = %+v", overs)
+	}
+}
+
+func TestPlaybackNavigation(t *testing.T) {
+	_, record := archivedBlindGame(t)
+	conn := &testConn{}
+	session := NewReplaySession(record, "carol", false, conn)
+	runSession(t, session,
+		`{"type":"prev"}`,
+		`{"type":"seek","data":{"step":-1}}`,
+		`{"type":"seek","data":{"step":3}}`,
+		`{"type":"prev"}`,
+		`{"type":"next"}`,
+		`{"type":"bogus"}`,
+	)
+	if session.step != 3 {
+		t.Errorf("ended at step %d, want 3", session.step)
+	}
+	var codes []string
+	for _, msg := range conn.messages(protocol.TypeError) {
+		codes = append(codes, msg.(protocol.Error).Code)
+	}
+	wantCodes := []string{"step_out_of_range", "step_out_of_range", "unknown_message_type"}
+	if len(codes) != len(wantCodes) {
+		t.Fatalf("error codes = %v, want %v", codes, wantCodes)
+	}
+	for i := range codes {
+		if codes[i] != wantCodes[i] {
+			t.Fatalf("error codes = %v, want %v", codes, wantCodes)
+		}
+	}
+	for _, msg := range conn.messages(protocol.TypeSync) {
+		for _, row := range msg.(protocol.Sync).Board {
+			for _, cell := range row {
+				checkCellView(t, "replay spectator", cell, false)
+			}
+		}
+	}
+}
+
+func TestPlaybackUnknownGame(t *testing.T) {
+	archive := NewArchive(1)
+	if _, ok := archive.Get("missing"); ok {
+		t.Fatal("found a game that was never saved")
+	}
+	var nilArchive *Archive
+	if _, ok := nilArchive.Get("missing"); ok {
+		t.Fatal("nil archive returned a game")
+	}
+	archive.Save(&GameRecord{GameID: "a", Log: &ActionLog{}})
+	archive.Save(&GameRecord{GameID: "b", Log: &ActionLog{}})
+	if _, ok := archive.Get("a"); ok {
+		t.Error("archive kept more games than its limit")
+	}
+}
+
+func itoa(v int) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"time"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+const DefaultReconnectGrace = 60 * time.Second
+
+func (r *Room) AttachConn(userID string, conn WebSocketConn) error {
+	player, err := r.playerByID(userID)
+	if err != nil {
+		return err
+	}
+	player.Conn = conn
+	player.Online = true
+	player.offlineSince = time.Time{}
+	// A new connection starts its own seq numbering.
+	player.lastSeq = 0
+	player.replies = nil
+	r.syncClockPause()
+	if r.Status != StatusWaiting {
+		r.sendStart(player)
+	}
+	if r.Status == StatusFinished {
+		r.sendTo(userID, protocol.TypeGameOver, r.gameOverData())
+	}
+	return nil
+}
+
+func (r *Room) DetachConn(userID string, conn WebSocketConn) bool {
+	player, err := r.playerByID(userID)
+	if err != nil || player.Conn != conn {
+		return false
+	}
+	r.markOffline(player)
+	return true
+}
+
+func (r *Room) markOffline(player *Player) {
+	player.Conn = nil
+	player.Online = false
+	if player.offlineSince.IsZero() {
+		player.offlineSince = r.now()
+	}
+	r.syncClockPause()
+}
+
+func (r *Room) reconnectGrace() time.Duration {
+	if r.ReconnectGrace <= 0 {
+		return DefaultReconnectGrace
+	}
+	return r.ReconnectGrace
+}
+
+func (r *Room) CheckAbandoned() bool {
+	if r.Status != StatusPlaying {
+		return false
+	}
+	now := r.now()
+	for _, player := range r.players() {
+		if player.Online || player.offlineSince.IsZero() {
+			continue
+		}
+		if now.Sub(player.offlineSince) >= r.reconnectGrace() {
+			r.forceFinish(player, "abandoned")
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) turnPlayerOffline() bool {
+	for _, player := range r.players() {
+		if player.Camp == r.Turn && !player.Online {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) syncClockPause() {
+	if r.turnStarted.IsZero() {
+		return
+	}
+	offline := r.turnPlayerOffline()
+	switch {
+	case offline && r.clockPausedAt.IsZero():
+		r.clockPausedAt = r.now()
+	case !offline && !r.clockPausedAt.IsZero():
+		paused := r.now().Sub(r.clockPausedAt)
+		r.turnStarted = r.turnStarted.Add(paused)
+		r.moveDeadline = r.moveDeadline.Add(paused)
+		r.clockPausedAt = time.Time{}
+	}
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+type replayTime struct {
+	t time.Time
+}
+
+func (c *replayTime) Now() time.Time {
+	return c.t
+}
+
+func Replay(seed int64, log *ActionLog) (*Room, error) {
+	clock := &replayTime{t: log.Started}
+	room := NewRoom("",
+		&Player{UserID: log.Player1, Camp: CampUnknown, Online: true},
+		&Player{UserID: log.Player2, Camp: CampUnknown, Online: true},
+		nil)
+	room.Rules = log.Rules
+	room.TimeSource = clock
+	if err := room.Deal(seed); err != nil {
+		return nil, err
+	}
+	room.Start(CampUnknown)
+	for _, entry := range log.Entries {
+		if _, err := room.replayEntry(entry); err != nil {
+			return nil, err
+		}
+	}
+	return room, nil
+}
+
+func (r *Room) replayEntry(entry ActionEntry) (*BattleResult, error) {
+	if clock, ok := r.TimeSource.(*replayTime); ok {
+		clock.t = entry.Time
+	}
+	if err := r.apply(entry); err != nil {
+		return nil, fmt.Errorf("replay step %d %s: %w", entry.Step, entry.Action, err)
+	}
+	return entry.Battle, nil
+}
+
+func (r *Room) apply(entry ActionEntry) error {
+	if entry.Step != r.Step {
+		return ErrReplayDiverged
+	}
+	switch entry.Action {
+	case ActionFlip:
+		return r.Flip(entry.UserID, entry.From.X, entry.From.Y)
+	case ActionMove:
+		battle, err := r.Move(entry.UserID, entry.From.X, entry.From.Y, entry.To.X, entry.To.Y)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(battle, entry.Battle) {
+			return ErrReplayDiverged
+		}
+	case ActionSurrender:
+		return r.Surrender(entry.UserID)
+	case ActionOfferDraw:
+		return r.OfferDraw(entry.UserID)
+	case ActionAcceptDraw:
+		return r.AcceptDraw(entry.UserID)
+	case ActionDeclineDraw:
+		return r.DeclineDraw(entry.UserID)
+	case ActionPass:
+		r.pass()
+	case ActionFinish:
+		if entry.UserID == "" {
+			r.forceFinish(nil, entry.Reason)
+			break
+		}
+		player, err := r.playerByID(entry.UserID)
+		if err != nil {
+			return err
+		}
+		r.forceFinish(player, entry.Reason)
+	default:
+		return fmt.Errorf("unknown action %q", entry.Action)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func playRandom(t *testing.T, r *Room, seed int64, actions int) {
+	t.Helper()
+	rng := rand.New(rand.NewSource(seed))
+	for i := 0; i < actions && r.Status == StatusPlaying; i++ {
+		userID := "alice"
+		if player, err := r.currentPlayer(); err == nil {
+			userID = player.UserID
+		}
+		switch n := rng.Intn(100); {
+		case n < 30:
+			_ = r.Flip(userID, rng.Intn(BoardCols), rng.Intn(BoardRows))
+		case n < 99:
+			_, _ = r.Move(userID, rng.Intn(BoardCols), rng.Intn(BoardRows), rng.Intn(BoardCols), rng.Intn(BoardRows))
+		default:
+			_ = r.OfferDraw(userID)
+		}
+	}
+}
+
+func blindAttacks(log *ActionLog) int {
+	n := 0
+	for _, entry := range log.Entries {
+		if entry.Battle != nil && entry.Battle.Revealed {
+			n++
+		}
+	}
+	return n
+}
+
+func customRules() Rules {
+	rules := LeagueRules()
+	rules.Name = ""
+	rules.BlindAttack = true
+	rules.FlagCapture = FlagCapturePolicy{
+		AllowedKinds:        []PieceKind{PieceEngineer, PiecePlatoon},
+		RequireMinesCleared: true,
+	}
+	return rules
+}
+
+func assertSameGame(t *testing.T, got, want *Room) {
+	t.Helper()
+	if !reflect.DeepEqual(got.Pieces, want.Pieces) || !reflect.DeepEqual(got.Board, want.Board) {
+		t.Fatal("replayed board differs")
+	}
+	if got.Step != want.Step || got.Turn != want.Turn || got.Status != want.Status ||
+		got.Winner != want.Winner || got.WinnerID != want.WinnerID || got.Reason != want.Reason ||
+		got.DrawOfferBy != want.DrawOfferBy {
+		t.Fatalf("replayed state differs: step %d/%d turn %s/%s status %s/%s", got.Step, want.Step, got.Turn, want.Turn, got.Status, want.Status)
+	}
+	if !reflect.DeepEqual(got.Log.Entries, want.Log.Entries) {
+		t.Fatal("replayed log differs")
+	}
+}
+
+func TestReplayReproducesGames(t *testing.T) {
+	blind := 0
+	for seed := int64(1); seed <= 20; seed++ {
+		r, _, _ := newTestRoom(t, seed)
+		r.Rules = customRules()
+		r.Log = r.newActionLog()
+		playRandom(t, r, seed, 3000)
+		blind += blindAttacks(r.Log)
+
+		replayed, err := Replay(seed, r.Log)
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		assertSameGame(t, replayed, r)
+	}
+	if blind == 0 {
+		t.Fatal("no blind attack was played")
+	}
+}
+
+func TestReplayFromSerializedLog(t *testing.T) {
+	r, _, _ := newTestRoom(t, 7)
+	r.Rules = customRules()
+	r.Log = r.newActionLog()
+	playRandom(t, r, 7, 3000)
+
+	raw, err := json.Marshal(r.Log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log ActionLog
+	if err := json.Unmarshal(raw, &log); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(log.Rules, r.Log.Rules) {
+		t.Fatalf("rules changed through JSON: %+v", log.Rules.FlagCapture)
+	}
+	replayed, err := Replay(log.Seed, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed.Pieces, r.Pieces) || replayed.Step != r.Step || replayed.Status != r.Status {
+		t.Fatal("replay from a serialized log differs")
+	}
+}
+
+func TestReplayIntermediateSteps(t *testing.T) {
+	r, _, _ := newTestRoom(t, 3)
+	r.Rules.BlindAttack = true
+	r.Log = r.newActionLog()
+	snapshots := make(map[int]string)
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 3000 && r.Status == StatusPlaying; i++ {
+		if _, ok := snapshots[r.Step]; !ok {
+			raw, _ := json.Marshal(r.SyncData(AdminViewer()))
+			snapshots[r.Step] = string(raw)
+		}
+		userID := "alice"
+		if player, err := r.currentPlayer(); err == nil {
+			userID = player.UserID
+		}
+		if rng.Intn(3) == 0 {
+			_ = r.Flip(userID, rng.Intn(BoardCols), rng.Intn(BoardRows))
+		} else {
+			_, _ = r.Move(userID, rng.Intn(BoardCols), rng.Intn(BoardRows), rng.Intn(BoardCols), rng.Intn(BoardRows))
+		}
+	}
+	for step, want := range snapshots {
+		replayed, err := Replay(r.Seed, r.Log.Upto(step))
+		if err != nil {
+			t.Fatalf("step %d: %v", step, err)
+		}
+		raw, _ := json.Marshal(replayed.SyncData(AdminViewer()))
+		if string(raw) != want {
+			t.Fatalf("step %d differs", step)
+		}
+	}
+}
+
+func TestReplayDetectsDivergence(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	r.Log = r.newActionLog()
+	playRandom(t, r, 1, 500)
+	if len(r.Log.Entries) < 2 {
+		t.Fatal("game too short")
+	}
+	log := *r.Log
+	log.Entries = append([]ActionEntry(nil), r.Log.Entries...)
+	log.Entries[1].Step++
+	if _, err := Replay(r.Seed, &log); err == nil {
+		t.Fatal("tampered log replayed cleanly")
+	}
+}
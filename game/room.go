@@ -1,27 +1,65 @@
 package game
 
-import (
-	"errors"
-	"fmt"
-)
+import "github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
 
 func NewRoom(roomID string, player1, player2 *Player, pieces map[string]*Piece) *Room {
-	board := NewBoard(BoardRows, BoardCols)
+	board := NewStandardBoard()
+	for _, piece := range pieces {
+		if piece.Alive {
+			_ = board.SetPiece(piece.X, piece.Y, piece.ID)
+		}
+	}
 	return &Room{
 		RoomID:  roomID,
 		Player1: player1,
 		Player2: player2,
 		Board:   board,
 		Pieces:  pieces,
+		Rules:   DefaultRules(),
 		Turn:    CampUnknown,
 		Status:  StatusWaiting,
 		Step:    0,
+
+		commands: make(chan roomCommand),
+		done:     make(chan struct{}),
 	}
 }
 
 func (r *Room) Start(turn string) {
 	r.Turn = turn
 	r.Status = StatusPlaying
+	for _, player := range r.players() {
+		if !player.Online {
+			player.offlineSince = r.now()
+		}
+	}
+	r.Log = r.newActionLog()
+	r.startClocks()
+	for _, player := range r.players() {
+		r.sendStart(player)
+	}
+	for _, s := range append([]*Spectator(nil), r.Spectators...) {
+		r.sendSpectator(s, protocol.TypeSync, r.SyncData(SpectatorViewer(s.UserID)))
+	}
+}
+
+func (r *Room) players() []*Player {
+	var players []*Player
+	for _, player := range []*Player{r.Player1, r.Player2} {
+		if player != nil {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+func (r *Room) campsUnknown() bool {
+	for _, player := range r.players() {
+		if player.Camp == CampUnknown {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *Room) currentPlayer() (*Player, error) {
@@ -31,7 +69,7 @@ func (r *Room) currentPlayer() (*Player, error) {
 	if r.Turn == r.Player2.Camp {
 		return r.Player2, nil
 	}
-	return nil, fmt.Errorf("turn camp not set")
+	return nil, ErrTurnNotSet
 }
 
 func (r *Room) playerByID(userID string) (*Player, error) {
@@ -41,7 +79,29 @@ func (r *Room) playerByID(userID string) (*Player, error) {
 	if r.Player2 != nil && r.Player2.UserID == userID {
 		return r.Player2, nil
 	}
-	return nil, fmt.Errorf("player not found")
+	return nil, ErrPlayerNotFound
+}
+
+func (r *Room) otherPlayer(player *Player) *Player {
+	if r.Player1 == player {
+		return r.Player2
+	}
+	if r.Player2 == player {
+		return r.Player1
+	}
+	return nil
+}
+
+func (r *Room) playerByCamp(camp string) *Player {
+	if camp != CampRed && camp != CampBlue {
+		return nil
+	}
+	for _, player := range r.players() {
+		if player.Camp == camp {
+			return player
+		}
+	}
+	return nil
 }
 
 func (r *Room) opponentCamp(camp string) string {
@@ -55,29 +115,32 @@ func (r *Room) opponentCamp(camp string) string {
 }
 
 func (r *Room) Flip(userID string, x, y int) error {
-	if r.Status != StatusPlaying {
-		return errors.New("room not playing")
+	step := r.Step
+	if err := r.flip(userID, x, y); err != nil {
+		return err
 	}
-	player, err := r.playerByID(userID)
+	r.record(ActionEntry{Step: step, Action: ActionFlip, UserID: userID, From: Pos{X: x, Y: y}})
+	return nil
+}
+
+func (r *Room) flip(userID string, x, y int) error {
+	player, err := r.actingPlayer(userID)
 	if err != nil {
 		return err
 	}
-	if player.Camp != CampUnknown && player.Camp != r.Turn {
-		return errors.New("not your turn")
-	}
 	cell, err := r.Board.GetCell(x, y)
 	if err != nil {
 		return err
 	}
 	if cell.PieceID == "" {
-		return errors.New("no piece to flip")
+		return ErrNoPieceToFlip
 	}
 	piece := r.Pieces[cell.PieceID]
 	if piece == nil {
-		return errors.New("piece not found")
+		return ErrPieceNotFound
 	}
 	if piece.Flipped {
-		return errors.New("piece already flipped")
+		return ErrPieceAlreadyFlipped
 	}
 	piece.Flipped = true
 	if player.Camp == CampUnknown {
@@ -92,43 +155,66 @@ func (r *Room) Flip(userID string, x, y int) error {
 			r.Turn = piece.Camp
 		}
 	}
-	r.advanceTurn()
+	r.checkGameOver()
+	if r.Status != StatusFinished {
+		r.advanceTurn()
+	}
 	return nil
 }
 
 func (r *Room) Move(userID string, fromX, fromY, toX, toY int) (*BattleResult, error) {
+	step := r.Step
+	battle, err := r.move(userID, fromX, fromY, toX, toY)
+	if err != nil {
+		return nil, err
+	}
+	r.record(ActionEntry{
+		Step:   step,
+		Action: ActionMove,
+		UserID: userID,
+		From:   Pos{X: fromX, Y: fromY},
+		To:     Pos{X: toX, Y: toY},
+		Battle: battle,
+	})
+	return battle, nil
+}
+
+func (r *Room) move(userID string, fromX, fromY, toX, toY int) (*BattleResult, error) {
 	if r.Status != StatusPlaying {
-		return nil, errors.New("room not playing")
+		return nil, ErrRoomNotPlaying
 	}
 	player, err := r.playerByID(userID)
 	if err != nil {
 		return nil, err
 	}
 	if player.Camp != r.Turn {
-		return nil, errors.New("not your turn")
+		return nil, ErrNotYourTurn
 	}
 	if !r.Board.InBounds(fromX, fromY) || !r.Board.InBounds(toX, toY) {
-		return nil, errors.New("out of bounds")
-	}
-	if abs(fromX-toX)+abs(fromY-toY) != 1 {
-		return nil, errors.New("invalid move")
+		return nil, ErrOutOfBounds
 	}
 	fromCell, _ := r.Board.GetCell(fromX, fromY)
 	if fromCell.PieceID == "" {
-		return nil, errors.New("no piece to move")
+		return nil, ErrNoPieceToMove
 	}
 	piece := r.Pieces[fromCell.PieceID]
 	if piece == nil || !piece.Alive {
-		return nil, errors.New("piece not available")
+		return nil, ErrPieceNotAvailable
 	}
 	if !piece.Flipped {
-		return nil, errors.New("piece not flipped")
+		return nil, ErrPieceNotFlipped
 	}
 	if piece.Camp != player.Camp {
-		return nil, errors.New("cannot move opponent piece")
+		return nil, ErrOpponentPiece
+	}
+	if piece.Type.Info().Immobile {
+		return nil, ErrPieceImmobile
+	}
+	if r.Board.IsHeadquarters(fromX, fromY) {
+		return nil, ErrPieceInHeadquarters
 	}
-	if piece.Type == PieceFlag || piece.Type == PieceMine {
-		return nil, errors.New("piece cannot move")
+	if !r.canReach(piece, fromX, fromY, toX, toY) {
+		return nil, ErrInvalidMove
 	}
 	toCell, _ := r.Board.GetCell(toX, toY)
 	if toCell.PieceID == "" {
@@ -140,17 +226,31 @@ func (r *Room) Move(userID string, fromX, fromY, toX, toY int) (*BattleResult, e
 		}
 		piece.X = toX
 		piece.Y = toY
-		r.advanceTurn()
+		r.checkGameOver()
+		if r.Status != StatusFinished {
+			r.advanceTurn()
+		}
 		return nil, nil
 	}
 	defender := r.Pieces[toCell.PieceID]
 	if defender == nil || !defender.Alive {
-		return nil, errors.New("defender not available")
+		return nil, ErrDefenderNotAvailable
 	}
-	if defender.Camp == player.Camp {
-		return nil, errors.New("cannot attack own piece")
+	revealed := false
+	if !defender.Flipped {
+		if !r.Rules.BlindAttack {
+			return nil, ErrDefenderNotFlipped
+		}
+		defender.Flipped = true
+		revealed = true
+		if r.checkAttack(piece, defender) != nil {
+			return r.blindAttackFailed(piece, defender), nil
+		}
+	} else if err := r.checkAttack(piece, defender); err != nil {
+		return nil, err
 	}
-	result := ResolveBattle(piece, defender)
+	result := ResolveBattleWith(r.Rules.battleMatrix(), piece, defender)
+	result.Revealed = revealed
 	switch {
 	case result.AttackerAlive && !result.DefenderAlive:
 		if err := r.Board.SetPiece(fromX, fromY, ""); err != nil {
@@ -173,6 +273,7 @@ func (r *Room) Move(userID string, fromX, fromY, toX, toY int) (*BattleResult, e
 			return nil, err
 		}
 	}
+	result.ApplyEffects(r, piece, defender)
 	result.CheckGameOver(r)
 	if r.Status != StatusFinished {
 		r.advanceTurn()
@@ -180,15 +281,87 @@ func (r *Room) Move(userID string, fromX, fromY, toX, toY int) (*BattleResult, e
 	return result, nil
 }
 
+func (r *Room) blindAttackFailed(attacker, defender *Piece) *BattleResult {
+	result := &BattleResult{
+		AttackerID:    attacker.ID,
+		DefenderID:    defender.ID,
+		AttackerType:  attacker.Type,
+		DefenderType:  defender.Type,
+		AttackerAlive: true,
+		DefenderAlive: true,
+		Result:        "repelled",
+		Revealed:      true,
+	}
+	if defender.Camp == attacker.Camp {
+		result.Result = "blunder"
+	}
+	r.checkGameOver()
+	if r.Status != StatusFinished {
+		r.advanceTurn()
+	}
+	return result
+}
+
+func (r *Room) checkAttack(attacker, defender *Piece) error {
+	if defender.Camp == attacker.Camp {
+		return ErrAttackOwnPiece
+	}
+	if r.Board.IsCamp(defender.X, defender.Y) {
+		return ErrDefenderInCamp
+	}
+	if r.Rules.battleMatrix().Outcome(attacker.Type, defender.Type) == OutcomeForbidden {
+		return ErrAttackNotAllowed
+	}
+	if defender.Type.Info().IsFlag {
+		return r.Rules.FlagCapture.check(r, attacker, defender)
+	}
+	return nil
+}
+
+func (r *Room) canReach(piece *Piece, fromX, fromY, toX, toY int) bool {
+	if r.Board.CanStep(fromX, fromY, toX, toY) {
+		return true
+	}
+	if !r.Rules.Railways {
+		return false
+	}
+	return r.Board.CanRail(fromX, fromY, toX, toY, piece.Type.Info().RailTurns)
+}
+
 func (r *Room) advanceTurn() {
+	r.expireDrawOffer()
+	r.chargeClock()
 	r.Turn = r.opponentCamp(r.Turn)
 	r.Step++
+	r.resetTurnClock()
 }
 
 func (r *Room) HasMovablePieces(camp string) bool {
 	for _, piece := range r.Pieces {
 		if piece.Alive && piece.Camp == camp && piece.Flipped {
-			if piece.Type != PieceFlag && piece.Type != PieceMine {
+			if r.hasLegalMove(piece) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Room) hasLegalMove(piece *Piece) bool {
+	if piece.Type.Info().Immobile || r.Board.IsHeadquarters(piece.X, piece.Y) {
+		return false
+	}
+	for y := 0; y < r.Board.Rows; y++ {
+		for x := 0; x < r.Board.Cols; x++ {
+			if !r.canReach(piece, piece.X, piece.Y, x, y) {
+				continue
+			}
+			target := r.Board.Cells[y][x].PieceID
+			if target == "" {
+				return true
+			}
+			defender := r.Pieces[target]
+			if defender != nil && defender.Alive && r.checkAttack(piece, defender) == nil {
 				return true
 			}
 		}
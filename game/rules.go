@@ -0,0 +1,96 @@
+package game
+
+import "fmt"
+
+type Rules struct {
+	Name                 string
+	Railways             bool
+	CommanderRevealsFlag bool
+	FlagCapture          FlagCapturePolicy
+	Battle               BattleMatrix
+	BlindAttack          bool
+}
+
+type FlagCapturePolicy struct {
+	AllowedKinds        []PieceKind
+	RequireMinesCleared bool
+}
+
+var standardBattleMatrix = StandardBattleMatrix()
+
+var rulesets = map[string]func() Rules{
+	"standard":   DefaultRules,
+	"simplified": SimplifiedRules,
+	"league":     LeagueRules,
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		Name:                 "standard",
+		Railways:             true,
+		CommanderRevealsFlag: true,
+		Battle:               standardBattleMatrix,
+	}
+}
+
+func SimplifiedRules() Rules {
+	return Rules{
+		Name:                 "simplified",
+		Railways:             false,
+		CommanderRevealsFlag: false,
+		Battle:               standardBattleMatrix,
+	}
+}
+
+// LeagueRules forbids bombing the flag and lets a mine survive a bomb.
+func LeagueRules() Rules {
+	rules := DefaultRules()
+	rules.Name = "league"
+	rules.Battle = standardBattleMatrix.
+		With(PieceBomb, PieceFlag, OutcomeForbidden).
+		With(PieceBomb, PieceMine, OutcomeDefenderWin)
+	return rules
+}
+
+func LookupRules(name string) (Rules, error) {
+	build, ok := rulesets[name]
+	if !ok {
+		return Rules{}, fmt.Errorf("unknown ruleset %q", name)
+	}
+	rules := build()
+	if err := rules.Battle.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+func (r Rules) battleMatrix() BattleMatrix {
+	if r.Battle == nil {
+		return standardBattleMatrix
+	}
+	return r.Battle
+}
+
+func (p FlagCapturePolicy) check(room *Room, attacker, flag *Piece) error {
+	if len(p.AllowedKinds) > 0 && !containsKind(p.AllowedKinds, attacker.Type) {
+		return ErrFlagCaptureNotAllowed
+	}
+	if p.RequireMinesCleared {
+		for _, pos := range room.Board.Neighbors(flag.X, flag.Y) {
+			guard := room.Pieces[room.Board.Cells[pos.Y][pos.X].PieceID]
+			if guard != nil && guard.Alive && guard.Flipped && guard.Camp == flag.Camp && guard.Type.Info().IsMine {
+				return ErrFlagGuarded
+			}
+		}
+	}
+	return nil
+}
+
+func containsKind(kinds []PieceKind, kind PieceKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
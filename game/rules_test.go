@@ -0,0 +1,33 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFlagGuardedOnlyByFlippedMines(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		flipped bool
+		want    error
+	}{
+		{"face-down mine", false, nil},
+		{"flipped mine", true, ErrFlagGuarded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pieces := map[string]*Piece{
+				"red-01":  {ID: "red-01", Type: PieceEngineer, Camp: CampRed, X: 0, Y: 0, Flipped: true, Alive: true},
+				"blue-25": {ID: "blue-25", Type: PieceFlag, Camp: CampBlue, X: 1, Y: 0, Flipped: true, Alive: true},
+				"blue-21": {ID: "blue-21", Type: PieceMine, Camp: CampBlue, X: 2, Y: 0, Flipped: tc.flipped, Alive: true},
+			}
+			r := NewRoom("guard",
+				&Player{UserID: "alice", Camp: CampRed},
+				&Player{UserID: "bob", Camp: CampBlue},
+				pieces)
+			r.Rules.FlagCapture = FlagCapturePolicy{RequireMinesCleared: true}
+			if err := r.checkAttack(pieces["red-01"], pieces["blue-25"]); !errors.Is(err, tc.want) {
+				t.Fatalf("checkAttack = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
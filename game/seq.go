@@ -0,0 +1,31 @@
+package game
+
+func (r *Room) sequenced(userID, action string, seq int64, expectedStep *int, handle func() error) error {
+	player, err := r.playerByID(userID)
+	if err != nil || seq <= 0 {
+		return r.checkStep(userID, action, expectedStep, handle)
+	}
+	switch {
+	case seq == player.lastSeq:
+		for _, reply := range player.replies {
+			r.send(player, reply.Type, reply.Data)
+		}
+		return nil
+	case seq < player.lastSeq:
+		r.sendError(userID, action, ErrStaleState)
+		return ErrStaleState
+	}
+	player.lastSeq = seq
+	player.replies = nil
+	player.recording = true
+	defer func() { player.recording = false }()
+	return r.checkStep(userID, action, expectedStep, handle)
+}
+
+func (r *Room) checkStep(userID, action string, expectedStep *int, handle func() error) error {
+	if expectedStep != nil && *expectedStep != r.Step {
+		r.sendError(userID, action, ErrStaleState)
+		return ErrStaleState
+	}
+	return handle()
+}
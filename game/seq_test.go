@@ -0,0 +1,85 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+func seqFlip(r *Room, seq int64, expectedStep *int) []byte {
+	piece := facePiece(r, true)
+	raw, _ := json.Marshal(protocol.Message{Type: protocol.TypeFlip, Data: mustJSON(protocol.FlipPayload{
+		X: piece.X, Y: piece.Y, Seq: seq, ExpectedStep: expectedStep,
+	})})
+	return raw
+}
+
+func TestRepeatedSeqReplaysReplies(t *testing.T) {
+	r, alice, _ := newTestRoom(t, 1)
+	before := len(alice.msgs)
+	flip := seqFlip(r, 1, nil)
+	if err := r.HandleMessage("alice", flip); err != nil {
+		t.Fatal(err)
+	}
+	replies := append([]protocol.Envelope(nil), alice.msgs[before:]...)
+	if len(replies) == 0 {
+		t.Fatal("flip sent no replies")
+	}
+	step := r.Step
+	if err := r.HandleMessage("alice", flip); err != nil {
+		t.Fatal(err)
+	}
+	if r.Step != step {
+		t.Fatalf("repeated seq advanced step to %d, want %d", r.Step, step)
+	}
+	if got := alice.msgs[before+len(replies):]; !reflect.DeepEqual(got, replies) {
+		t.Fatalf("replayed %+v, want %+v", got, replies)
+	}
+}
+
+func TestStaleSeqAndExpectedStep(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	if err := r.HandleMessage("alice", seqFlip(r, 1, nil)); err != nil {
+		t.Fatal(err)
+	}
+	step := r.Step
+	old := step - 1
+	if err := r.HandleMessage("bob", seqFlip(r, 3, &old)); !errors.Is(err, ErrStaleState) {
+		t.Fatalf("wrong expectedStep = %v, want %v", err, ErrStaleState)
+	}
+	if r.Step != step {
+		t.Fatalf("rejected flip advanced step to %d", r.Step)
+	}
+	if err := r.HandleMessage("bob", seqFlip(r, 4, &step)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.HandleMessage("bob", seqFlip(r, 2, nil)); !errors.Is(err, ErrStaleState) {
+		t.Fatalf("lower seq = %v, want %v", err, ErrStaleState)
+	}
+	if r.Step != step+1 {
+		t.Fatalf("step %d, want %d", r.Step, step+1)
+	}
+}
+
+func TestReconnectResetsSeq(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	if err := r.HandleMessage("alice", seqFlip(r, 5, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.HandleMessage("bob", seqFlip(r, 0, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AttachConn("alice", &testConn{}); err != nil {
+		t.Fatal(err)
+	}
+	step := r.Step
+	if err := r.HandleMessage("alice", seqFlip(r, 1, nil)); err != nil {
+		t.Fatalf("restarted seq rejected: %v", err)
+	}
+	if r.Step != step+1 {
+		t.Fatalf("step %d, want %d", r.Step, step+1)
+	}
+}
@@ -0,0 +1,43 @@
+package game
+
+import "fmt"
+
+func ValidateClassicSetup(board *Board, pieces map[string]*Piece, camp string) error {
+	counts := make(map[PieceKind]int)
+	for _, piece := range pieces {
+		if piece.Camp != camp || !piece.Alive {
+			continue
+		}
+		counts[piece.Type]++
+		cell, err := board.GetCell(piece.X, piece.Y)
+		if err != nil {
+			return fmt.Errorf("piece %s: %w", piece.ID, err)
+		}
+		if cell.PieceID != piece.ID {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupOffBoard)
+		}
+		if HomeCamp(piece.Y) != camp {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupOutsideHalf)
+		}
+		if cell.Kind == CellCamp {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupInCamp)
+		}
+		row := homeRow(camp, piece.Y)
+		info := piece.Type.Info()
+		if info.IsFlag && cell.Kind != CellHeadquarters {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupFlagPlacement)
+		}
+		if info.IsMine && row > 1 {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupMinePlacement)
+		}
+		if info.DestroysAll && row == BoardRows/2-1 {
+			return fmt.Errorf("piece %s: %w", piece.ID, ErrSetupBombPlacement)
+		}
+	}
+	for _, info := range pieceCatalogue {
+		if counts[info.Kind] != info.Count {
+			return fmt.Errorf("%s: expected %d, got %d: %w", info.NameEn, info.Count, counts[info.Kind], ErrSetupComposition)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// classicSetup places a legal red army: flag and mines at the back, bombs on
+// the second row and everything else filling the remaining posts.
+func classicSetup() (*Board, map[string]*Piece) {
+	var kinds []PieceKind
+	for _, kind := range []PieceKind{PieceFlag, PieceMine, PieceBomb} {
+		for i := 0; i < kind.Info().Count; i++ {
+			kinds = append(kinds, kind)
+		}
+	}
+	for _, info := range pieceCatalogue {
+		if info.Kind == PieceFlag || info.Kind == PieceMine || info.Kind == PieceBomb {
+			continue
+		}
+		for i := 0; i < info.Count; i++ {
+			kinds = append(kinds, info.Kind)
+		}
+	}
+	cells := []Pos{{1, 11}, {0, 11}, {2, 11}, {3, 11}, {0, 10}, {1, 10}}
+	for y := BoardRows - 1; y >= BoardRows/2; y-- {
+		for x := 0; x < BoardCols; x++ {
+			p := Pos{x, y}
+			if containsPos(cells, p) || standardLayout[y][x] == 'C' {
+				continue
+			}
+			cells = append(cells, p)
+		}
+	}
+	board := NewStandardBoard()
+	pieces := make(map[string]*Piece)
+	for i, kind := range kinds {
+		id := fmt.Sprintf("red-%02d", i+1)
+		pieces[id] = &Piece{ID: id, Type: kind, Camp: CampRed, X: cells[i].X, Y: cells[i].Y, Alive: true}
+		_ = board.SetPiece(cells[i].X, cells[i].Y, id)
+	}
+	return board, pieces
+}
+
+func containsPos(list []Pos, p Pos) bool {
+	for _, q := range list {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+func pieceAt(pieces map[string]*Piece, x, y int) *Piece {
+	for _, piece := range pieces {
+		if piece.X == x && piece.Y == y {
+			return piece
+		}
+	}
+	return nil
+}
+
+func swapPieces(board *Board, a, b *Piece) {
+	a.X, a.Y, b.X, b.Y = b.X, b.Y, a.X, a.Y
+	_ = board.SetPiece(a.X, a.Y, a.ID)
+	_ = board.SetPiece(b.X, b.Y, b.ID)
+}
+
+func relocate(board *Board, piece *Piece, x, y int) {
+	_ = board.SetPiece(piece.X, piece.Y, "")
+	piece.X, piece.Y = x, y
+	_ = board.SetPiece(x, y, piece.ID)
+}
+
+func TestValidateClassicSetup(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		mutate func(*Board, map[string]*Piece)
+		want   error
+	}{
+		{"valid layout", func(*Board, map[string]*Piece) {}, nil},
+		{"flag outside headquarters", func(b *Board, p map[string]*Piece) {
+			swapPieces(b, pieceAt(p, 1, 11), pieceAt(p, 2, 7))
+		}, ErrSetupFlagPlacement},
+		{"mine beyond the last two rows", func(b *Board, p map[string]*Piece) {
+			swapPieces(b, pieceAt(p, 0, 11), pieceAt(p, 0, 9))
+		}, ErrSetupMinePlacement},
+		{"bomb in the front row", func(b *Board, p map[string]*Piece) {
+			swapPieces(b, pieceAt(p, 0, 10), pieceAt(p, 0, 6))
+		}, ErrSetupBombPlacement},
+		{"piece in a camp", func(b *Board, p map[string]*Piece) {
+			relocate(b, pieceAt(p, 0, 6), 1, 7)
+		}, ErrSetupInCamp},
+		{"piece outside its half", func(b *Board, p map[string]*Piece) {
+			relocate(b, pieceAt(p, 0, 6), 0, 5)
+		}, ErrSetupOutsideHalf},
+		{"piece missing from the board", func(b *Board, p map[string]*Piece) {
+			_ = b.SetPiece(0, 6, "")
+		}, ErrSetupOffBoard},
+		{"wrong composition", func(b *Board, p map[string]*Piece) {
+			for _, piece := range p {
+				if piece.Type == PiecePlatoon {
+					piece.Type = PieceCompany
+					return
+				}
+			}
+		}, ErrSetupComposition},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			board, pieces := classicSetup()
+			tc.mutate(board, pieces)
+			err := ValidateClassicSetup(board, pieces, CampRed)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("valid layout rejected: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("ValidateClassicSetup = %v, want %v", err, tc.want)
+			}
+			if ErrorCode(err) != ErrorCode(tc.want) {
+				t.Fatalf("code = %q, want %q", ErrorCode(err), ErrorCode(tc.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package game
+
+import (
+	"encoding/json"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+type Spectator struct {
+	UserID string
+	Conn   WebSocketConn
+}
+
+func (r *Room) AddSpectator(userID string, conn WebSocketConn) {
+	r.Spectators = append(r.Spectators, &Spectator{UserID: userID, Conn: conn})
+	if r.Status != StatusWaiting {
+		r.sendSpectator(r.Spectators[len(r.Spectators)-1], protocol.TypeSync, r.SyncData(SpectatorViewer(userID)))
+	}
+}
+
+func (r *Room) HandleSpectatorMessage(conn WebSocketConn, raw []byte) {
+	var msg protocol.Message
+	if err := json.Unmarshal(raw, &msg); err != nil || msg.Type != protocol.TypeResync {
+		return
+	}
+	for _, s := range r.Spectators {
+		if s.Conn == conn {
+			r.sendSpectator(s, protocol.TypeSync, r.SyncData(SpectatorViewer(s.UserID)))
+			return
+		}
+	}
+}
+
+func (r *Room) RemoveSpectator(conn WebSocketConn) {
+	kept := r.Spectators[:0]
+	for _, s := range r.Spectators {
+		if s.Conn != conn {
+			kept = append(kept, s)
+		}
+	}
+	r.Spectators = kept
+}
+
+func (r *Room) sendSpectator(s *Spectator, msgType string, data any) {
+	if err := s.Conn.WriteJSON(protocol.Envelope{Type: msgType, Data: data}); err != nil {
+		r.RemoveSpectator(s.Conn)
+	}
+}
@@ -0,0 +1,62 @@
+package game
+
+func (r *Room) CanAct(camp string) bool {
+	return r.hasFaceDownPieces() || r.HasMovablePieces(camp)
+}
+
+func (r *Room) hasFaceDownPieces() bool {
+	for _, piece := range r.Pieces {
+		if piece.Alive && !piece.Flipped {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) checkGameOver() {
+	if r.Status == StatusFinished {
+		return
+	}
+	redCanAct := r.CanAct(CampRed)
+	blueCanAct := r.CanAct(CampBlue)
+	switch {
+	case !redCanAct && !blueCanAct:
+		r.finish("", "stalemate")
+	case !redCanAct:
+		r.finish(CampBlue, "no_movable_pieces")
+	case !blueCanAct:
+		r.finish(CampRed, "no_movable_pieces")
+	}
+}
+
+func (r *Room) forfeit(loser *Player, reason string) {
+	winner := r.otherPlayer(loser)
+	if winner == nil {
+		r.finish("", reason)
+		return
+	}
+	r.WinnerID = winner.UserID
+	camp := winner.Camp
+	if camp == CampUnknown {
+		camp = ""
+	}
+	r.finish(camp, reason)
+}
+
+func (r *Room) finish(winner, reason string) {
+	if r.WinnerID == "" {
+		if player := r.playerByCamp(winner); player != nil {
+			r.WinnerID = player.UserID
+		}
+	}
+	r.Winner = winner
+	r.Reason = reason
+	r.Status = StatusFinished
+}
+
+func (r *Room) afterFinish() {
+	r.archiveGame()
+	if r.onFinish != nil {
+		r.onFinish(r)
+	}
+}
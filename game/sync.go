@@ -1,9 +1,34 @@
 package game
 
-func (r *Room) SyncData() map[string]any {
-	boardView := make([][]map[string]any, r.Board.Rows)
+import "github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+
+const (
+	ViewerPlayer    = "player"
+	ViewerSpectator = "spectator"
+	ViewerAdmin     = "admin"
+)
+
+type Viewer struct {
+	Kind   string
+	UserID string
+}
+
+func PlayerViewer(userID string) Viewer {
+	return Viewer{Kind: ViewerPlayer, UserID: userID}
+}
+
+func SpectatorViewer(userID string) Viewer {
+	return Viewer{Kind: ViewerSpectator, UserID: userID}
+}
+
+func AdminViewer() Viewer {
+	return Viewer{Kind: ViewerAdmin}
+}
+
+func (r *Room) SyncData(viewer Viewer) protocol.Sync {
+	boardView := make([][]*protocol.CellView, r.Board.Rows)
 	for y := 0; y < r.Board.Rows; y++ {
-		boardView[y] = make([]map[string]any, r.Board.Cols)
+		boardView[y] = make([]*protocol.CellView, r.Board.Cols)
 		for x := 0; x < r.Board.Cols; x++ {
 			cell := r.Board.Cells[y][x]
 			if cell.PieceID == "" {
@@ -13,20 +38,38 @@ func (r *Room) SyncData() map[string]any {
 			if piece == nil {
 				continue
 			}
-			entry := map[string]any{
-				"id":      piece.ID,
-				"flipped": piece.Flipped,
-			}
-			if piece.Flipped {
-				entry["type"] = piece.Type
-				entry["camp"] = piece.Camp
-			}
-			boardView[y][x] = entry
+			boardView[y][x] = r.cellView(viewer, piece)
 		}
 	}
-	return map[string]any{
-		"board": boardView,
-		"turn":  r.Turn,
-		"step":  r.Step,
+	return protocol.Sync{
+		Board: boardView,
+		Turn:  r.Turn,
+		Step:  r.Step,
+		Clock: r.clockView(),
+	}
+}
+
+func (r *Room) cellView(viewer Viewer, piece *Piece) *protocol.CellView {
+	entry := &protocol.CellView{
+		ID:      piece.ID,
+		Flipped: piece.Flipped,
+	}
+	exposed := piece.Exposed && r.seesExposed(viewer, piece)
+	if piece.Flipped || exposed || viewer.Kind == ViewerAdmin {
+		entry.Type = piece.Type.String()
+		entry.Camp = piece.Camp
+	}
+	entry.Exposed = exposed
+	return entry
+}
+
+func (r *Room) seesExposed(viewer Viewer, piece *Piece) bool {
+	switch viewer.Kind {
+	case ViewerAdmin:
+		return true
+	case ViewerPlayer:
+		player, err := r.playerByID(viewer.UserID)
+		return err == nil && player.Camp == r.opponentCamp(piece.Camp)
 	}
+	return false
 }
@@ -0,0 +1,227 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
+func checkCellView(t *testing.T, who string, cell *protocol.CellView, mayExpose bool) bool {
+	t.Helper()
+	if cell == nil || cell.Flipped {
+		return false
+	}
+	if cell.Exposed && mayExpose {
+		return true
+	}
+	if cell.Type != "" || cell.Camp != "" || cell.Exposed {
+		t.Fatalf("%s sees face-down %s as %q/%q (exposed %v)", who, cell.ID, cell.Type, cell.Camp, cell.Exposed)
+	}
+	return false
+}
+
+func checkProjection(t *testing.T, r *Room, viewer Viewer) int {
+	t.Helper()
+	mayExpose := viewer.Kind == ViewerPlayer
+	exposed := 0
+	for _, row := range r.SyncData(viewer).Board {
+		for _, cell := range row {
+			if checkCellView(t, viewer.Kind+" "+viewer.UserID, cell, mayExpose) {
+				exposed++
+				piece := r.Pieces[cell.ID]
+				player, _ := r.playerByID(viewer.UserID)
+				if player.Camp == piece.Camp {
+					t.Fatalf("%s sees own face-down flag %s exposed", viewer.UserID, cell.ID)
+				}
+			}
+		}
+	}
+	return exposed
+}
+
+func checkSpectatorMessages(t *testing.T, conn *testConn) {
+	t.Helper()
+	for _, msg := range conn.messages(protocol.TypeDelta) {
+		for _, change := range msg.(protocol.Delta).Cells {
+			checkCellView(t, "spectator delta", change.Cell, false)
+		}
+	}
+	for _, msg := range conn.messages(protocol.TypeBattle) {
+		if reveals := msg.(protocol.Battle).Reveals; len(reveals) > 0 {
+			t.Fatalf("spectator received flag reveals %+v", reveals)
+		}
+	}
+}
+
+func TestViewsNeverLeakFaceDownPieces(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		r, _, _ := newTestRoom(t, seed)
+		r.Rules.BlindAttack = true
+		watcher := &testConn{}
+		r.AddSpectator("carol", watcher)
+		rng := rand.New(rand.NewSource(seed))
+		for i := 0; i < 1500 && r.Status == StatusPlaying; i++ {
+			userID := "alice"
+			if player, err := r.currentPlayer(); err == nil {
+				userID = player.UserID
+			}
+			var raw []byte
+			if rng.Intn(4) == 0 {
+				raw, _ = json.Marshal(protocol.Message{Type: protocol.TypeFlip, Data: mustJSON(protocol.FlipPayload{X: rng.Intn(BoardCols), Y: rng.Intn(BoardRows)})})
+			} else {
+				raw, _ = json.Marshal(protocol.Message{Type: protocol.TypeMove, Data: mustJSON(protocol.MovePayload{
+					FromX: rng.Intn(BoardCols), FromY: rng.Intn(BoardRows),
+					ToX: rng.Intn(BoardCols), ToY: rng.Intn(BoardRows),
+				})})
+			}
+			if r.HandleMessage(userID, raw) != nil {
+				continue
+			}
+			checkProjection(t, r, PlayerViewer("alice"))
+			checkProjection(t, r, PlayerViewer("bob"))
+			checkProjection(t, r, SpectatorViewer("carol"))
+		}
+		checkSpectatorMessages(t, watcher)
+	}
+}
+
+func TestExposedFlagOnlyReachesOpponent(t *testing.T) {
+	pieces := map[string]*Piece{
+		"red-01":  {ID: "red-01", Type: PieceCommander, Camp: CampRed, X: 0, Y: 6, Flipped: true, Alive: true},
+		"red-25":  {ID: "red-25", Type: PieceFlag, Camp: CampRed, X: 1, Y: 11, Alive: true},
+		"blue-20": {ID: "blue-20", Type: PieceBomb, Camp: CampBlue, X: 0, Y: 5, Flipped: true, Alive: true},
+		"blue-25": {ID: "blue-25", Type: PieceFlag, Camp: CampBlue, X: 1, Y: 0, Alive: true},
+	}
+	alice, bob, watcher := &testConn{}, &testConn{}, &testConn{}
+	r := NewRoom("exposed",
+		&Player{UserID: "alice", Camp: CampRed, Online: true, Conn: alice},
+		&Player{UserID: "bob", Camp: CampBlue, Online: true, Conn: bob},
+		pieces)
+	r.Start(CampBlue)
+	r.AddSpectator("carol", watcher)
+
+	raw, _ := json.Marshal(protocol.Message{Type: protocol.TypeMove, Data: mustJSON(protocol.MovePayload{FromX: 0, FromY: 5, ToX: 0, ToY: 6})})
+	if err := r.HandleMessage("bob", raw); err != nil {
+		t.Fatal(err)
+	}
+	if !pieces["red-25"].Exposed {
+		t.Fatal("commander death did not expose the flag")
+	}
+	if n := checkProjection(t, r, PlayerViewer("bob")); n != 1 {
+		t.Errorf("opponent sees %d exposed flags, want 1", n)
+	}
+	checkProjection(t, r, PlayerViewer("alice"))
+	checkProjection(t, r, SpectatorViewer("carol"))
+
+	reveals := func(conn *testConn) int {
+		n := 0
+		for _, msg := range conn.messages(protocol.TypeBattle) {
+			n += len(msg.(protocol.Battle).Reveals)
+		}
+		return n
+	}
+	if got := reveals(bob); got != 1 {
+		t.Errorf("opponent got %d reveals, want 1", got)
+	}
+	if got := reveals(alice); got != 0 {
+		t.Errorf("flag owner got %d reveals, want 0", got)
+	}
+	checkSpectatorMessages(t, watcher)
+	for _, conn := range []*testConn{alice, watcher} {
+		for _, msg := range conn.messages(protocol.TypeDelta) {
+			for _, change := range msg.(protocol.Delta).Cells {
+				checkCellView(t, "delta", change.Cell, false)
+			}
+		}
+	}
+}
+
+func TestSpectatorJoiningWaitingRoomGetsSync(t *testing.T) {
+	conn1, conn2, watcher := &testConn{}, &testConn{}, &testConn{}
+	r := NewRoom("early",
+		&Player{UserID: "alice", Camp: CampUnknown, Online: true, Conn: conn1},
+		&Player{UserID: "bob", Camp: CampUnknown, Online: true, Conn: conn2},
+		nil)
+	if err := r.Deal(1); err != nil {
+		t.Fatal(err)
+	}
+	r.AddSpectator("carol", watcher)
+	if n := len(watcher.messages(protocol.TypeSync)); n != 0 {
+		t.Fatalf("spectator got %d syncs before start", n)
+	}
+	r.Start(CampUnknown)
+	piece := facePiece(r, true)
+	if err := r.HandleMessage("alice", flipMessage(piece.X, piece.Y)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(watcher.messages(protocol.TypeSync)); n != 1 {
+		t.Fatalf("spectator got %d syncs, want 1", n)
+	}
+	if n := len(watcher.messages(protocol.TypeDelta)); n != 1 {
+		t.Fatalf("spectator got %d deltas, want 1", n)
+	}
+	if watcher.msgs[0].Type != protocol.TypeSync {
+		t.Fatalf("spectator's first message is %s, want sync", watcher.msgs[0].Type)
+	}
+}
+
+func TestFinishingFlipSendsDeltaBeforeGameOver(t *testing.T) {
+	pieces := map[string]*Piece{
+		"red-21":  {ID: "red-21", Type: PieceMine, Camp: CampRed, X: 0, Y: 10, Alive: true},
+		"red-25":  {ID: "red-25", Type: PieceFlag, Camp: CampRed, X: 1, Y: 11, Flipped: true, Alive: true},
+		"blue-01": {ID: "blue-01", Type: PieceCommander, Camp: CampBlue, X: 2, Y: 1, Flipped: true, Alive: true},
+	}
+	alice, bob := &testConn{}, &testConn{}
+	r := NewRoom("last-flip",
+		&Player{UserID: "alice", Camp: CampRed, Online: true, Conn: alice},
+		&Player{UserID: "bob", Camp: CampBlue, Online: true, Conn: bob},
+		pieces)
+	r.Start(CampRed)
+	if err := r.HandleMessage("alice", flipMessage(0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != StatusFinished || r.WinnerID != "bob" {
+		t.Fatalf("status %s winner %q, want bob to win", r.Status, r.WinnerID)
+	}
+	for _, conn := range []*testConn{alice, bob} {
+		var types []string
+		for _, msg := range conn.msgs {
+			if msg.Type == protocol.TypeDelta || msg.Type == protocol.TypeGameOver {
+				types = append(types, msg.Type)
+			}
+		}
+		if len(types) != 2 || types[0] != protocol.TypeDelta || types[1] != protocol.TypeGameOver {
+			t.Fatalf("got %v, want delta then game_over", types)
+		}
+		cell := conn.messages(protocol.TypeDelta)[0].(protocol.Delta).Cells[0].Cell
+		if cell == nil || cell.Type != PieceMine.String() {
+			t.Fatalf("delta does not reveal the deciding piece: %+v", cell)
+		}
+	}
+}
+
+func TestAdminSeesFaceDownPieces(t *testing.T) {
+	r, _, _ := newTestRoom(t, 1)
+	for _, row := range r.SyncData(AdminViewer()).Board {
+		for _, cell := range row {
+			if cell != nil && (cell.Type == "" || cell.Camp == "") {
+				t.Fatalf("admin view hides %s", cell.ID)
+			}
+		}
+	}
+}
+
+func flipMessage(x, y int) []byte {
+	raw, _ := json.Marshal(protocol.Message{Type: protocol.TypeFlip, Data: mustJSON(protocol.FlipPayload{X: x, Y: y})})
+	return raw
+}
+
+func mustJSON(v any) json.RawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
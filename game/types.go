@@ -1,17 +1,23 @@
 package game
 
+import (
+	"time"
+
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
+
 type WebSocketConn interface {
 	WriteJSON(v any) error
 }
 
 type Piece struct {
 	ID      string
-	Type    string
+	Type    PieceKind
 	Camp    string
-	Rank    int
 	X       int
 	Y       int
 	Flipped bool
+	Exposed bool
 	Alive   bool
 }
 
@@ -20,17 +26,47 @@ type Player struct {
 	Camp   string
 	Online bool
 	Conn   WebSocketConn
+
+	offlineSince time.Time
+	lastSeq      int64
+	replies      []protocol.Envelope
+	recording    bool
 }
 
 type Room struct {
-	RoomID  string
-	Player1 *Player
-	Player2 *Player
-	Board   *Board
-	Pieces  map[string]*Piece
-	Turn    string
-	Status  string
-	Winner  string
-	Reason  string
-	Step    int
+	RoomID   string
+	Player1  *Player
+	Player2  *Player
+	Board    *Board
+	Pieces   map[string]*Piece
+	Rules    Rules
+	Seed     int64
+	Turn     string
+	Status   string
+	Winner   string
+	WinnerID string
+	Reason   string
+	Step     int
+
+	Log            *ActionLog
+	Spectators     []*Spectator
+	DrawOfferBy    string
+	TimeControl    *TimeControl
+	TimeSource     TimeSource
+	ReconnectGrace time.Duration
+	Archive        *Archive
+
+	commands   chan roomCommand
+	done       chan struct{}
+	lastActive time.Time
+	onFinish   func(*Room)
+
+	clocks        map[string]*PlayerClock
+	turnStarted   time.Time
+	moveDeadline  time.Time
+	clockPausedAt time.Time
+}
+
+func (p *Piece) Rank() int {
+	return p.Type.Info().Rank
 }
@@ -2,104 +2,229 @@ package game
 
 import (
 	"encoding/json"
-	"errors"
-)
-
-type Message struct {
-	Type string          `json:"type"`
-	Data json.RawMessage `json:"data"`
-}
-
-type FlipPayload struct {
-	X int `json:"x"`
-	Y int `json:"y"`
-}
 
-type MovePayload struct {
-	FromX int `json:"fromX"`
-	FromY int `json:"fromY"`
-	ToX   int `json:"toX"`
-	ToY   int `json:"toY"`
-}
+	"github.com/m17604895278/GPT-Codex-Land-Chess-Go/protocol"
+)
 
 func (r *Room) HandleMessage(userID string, raw []byte) error {
-	var msg Message
+	r.lastActive = r.now()
+	var msg protocol.Message
 	if err := json.Unmarshal(raw, &msg); err != nil {
-		return err
+		r.sendError(userID, "", ErrBadPayload)
+		return ErrBadPayload
 	}
 	switch msg.Type {
-	case "flip":
-		var payload FlipPayload
+	case protocol.TypeFlip:
+		var payload protocol.FlipPayload
 		if err := json.Unmarshal(msg.Data, &payload); err != nil {
-			return err
+			r.sendError(userID, msg.Type, ErrBadPayload)
+			return ErrBadPayload
 		}
-		if err := r.Flip(userID, payload.X, payload.Y); err != nil {
-			r.sendError(userID, err.Error())
+		return r.sequenced(userID, msg.Type, payload.Seq, payload.ExpectedStep, func() error {
+			return r.handleFlip(userID, payload)
+		})
+	case protocol.TypeMove:
+		var payload protocol.MovePayload
+		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+			r.sendError(userID, msg.Type, ErrBadPayload)
+			return ErrBadPayload
+		}
+		return r.sequenced(userID, msg.Type, payload.Seq, payload.ExpectedStep, func() error {
+			return r.handleMove(userID, payload)
+		})
+	case protocol.TypeSurrender:
+		if err := r.Surrender(userID); err != nil {
+			r.sendError(userID, msg.Type, err)
 			return err
 		}
-		r.broadcast("sync", r.SyncData())
-	case "move":
-		var payload MovePayload
-		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		r.broadcastGameOver()
+	case protocol.TypeOfferDraw:
+		if err := r.OfferDraw(userID); err != nil {
+			r.sendError(userID, msg.Type, err)
 			return err
 		}
-		battle, err := r.Move(userID, payload.FromX, payload.FromY, payload.ToX, payload.ToY)
-		if err != nil {
-			r.sendError(userID, err.Error())
+		r.broadcastDrawOffer(protocol.TypeDrawOffered, userID)
+	case protocol.TypeAcceptDraw:
+		if err := r.AcceptDraw(userID); err != nil {
+			r.sendError(userID, msg.Type, err)
 			return err
 		}
-		if battle != nil {
-			r.broadcast("battle", map[string]any{
-				"from":     []int{payload.FromX, payload.FromY},
-				"to":       []int{payload.ToX, payload.ToY},
-				"attacker": battle.AttackerType,
-				"defender": battle.DefenderType,
-				"result":   battle.Result,
-			})
+		r.broadcastGameOver()
+	case protocol.TypeDeclineDraw:
+		offerer := r.DrawOfferBy
+		if err := r.DeclineDraw(userID); err != nil {
+			r.sendError(userID, msg.Type, err)
+			return err
 		}
-		if r.Status == StatusFinished {
-			r.broadcast("game_over", map[string]any{
-				"winner": r.Winner,
-				"reason": r.Reason,
+		r.broadcastDrawOffer(protocol.TypeDrawDeclined, offerer)
+	case protocol.TypeResync:
+		r.sendSync(userID)
+	case protocol.TypePing:
+		r.sendTo(userID, protocol.TypePong, protocol.Pong{TS: r.nowUnix()})
+	default:
+		r.sendError(userID, msg.Type, ErrUnknownMessage)
+		return ErrUnknownMessage
+	}
+	return nil
+}
+
+func (r *Room) handleFlip(userID string, payload protocol.FlipPayload) error {
+	campsUnknown := r.campsUnknown()
+	if err := r.Flip(userID, payload.X, payload.Y); err != nil {
+		r.sendError(userID, protocol.TypeFlip, err)
+		return err
+	}
+	if campsUnknown && !r.campsUnknown() {
+		for _, player := range r.players() {
+			r.sendTo(player.UserID, protocol.TypeCampAssigned, protocol.CampAssigned{
+				YouCamp: player.Camp,
+				Turn:    r.Turn,
 			})
-			return nil
 		}
-		r.broadcast("sync", r.SyncData())
-	case "ping":
-		r.sendTo(userID, "pong", map[string]any{"ts": nowUnix()})
-	default:
-		r.sendError(userID, "unknown message type")
-		return errors.New("unknown message type")
+	}
+	r.broadcastDelta(r.lastMove(userID, protocol.TypeFlip, []int{payload.X, payload.Y}, nil),
+		[]Pos{{X: payload.X, Y: payload.Y}})
+	if r.Status == StatusFinished {
+		r.broadcastGameOver()
 	}
 	return nil
 }
 
-func (r *Room) broadcast(msgType string, data map[string]any) {
-	if r.Player1 != nil {
-		_ = r.Player1.Conn.WriteJSON(map[string]any{
-			"type": msgType,
-			"data": data,
+func (r *Room) handleMove(userID string, payload protocol.MovePayload) error {
+	battle, err := r.Move(userID, payload.FromX, payload.FromY, payload.ToX, payload.ToY)
+	if err != nil {
+		r.sendError(userID, protocol.TypeMove, err)
+		return err
+	}
+	from, to := Pos{X: payload.FromX, Y: payload.FromY}, Pos{X: payload.ToX, Y: payload.ToY}
+	if battle != nil {
+		r.broadcastView(protocol.TypeBattle, func(viewer Viewer) any {
+			return r.battleMessage(viewer, from, to, battle)
 		})
 	}
-	if r.Player2 != nil {
-		_ = r.Player2.Conn.WriteJSON(map[string]any{
-			"type": msgType,
-			"data": data,
+	r.broadcastDelta(r.lastMove(userID, protocol.TypeMove, []int{from.X, from.Y}, []int{to.X, to.Y}),
+		battleChanges(from, to, battle))
+	if r.Status == StatusFinished {
+		r.broadcastGameOver()
+	}
+	return nil
+}
+
+func (r *Room) broadcast(msgType string, data any) {
+	r.broadcastView(msgType, func(Viewer) any { return data })
+}
+
+func (r *Room) broadcastView(msgType string, build func(Viewer) any) {
+	for _, player := range r.players() {
+		if player.Conn != nil {
+			r.send(player, msgType, build(PlayerViewer(player.UserID)))
+		}
+	}
+	for _, s := range append([]*Spectator(nil), r.Spectators...) {
+		r.sendSpectator(s, msgType, build(SpectatorViewer(s.UserID)))
+	}
+}
+
+func (r *Room) broadcastSync() {
+	r.broadcastView(protocol.TypeSync, func(viewer Viewer) any {
+		return r.SyncData(viewer)
+	})
+}
+
+func (r *Room) lastMove(userID, action string, from, to []int) *protocol.LastMove {
+	lastMove := &protocol.LastMove{Type: action, From: from, To: to}
+	if player, err := r.playerByID(userID); err == nil {
+		lastMove.Camp = player.Camp
+	}
+	return lastMove
+}
+
+func (r *Room) battleMessage(viewer Viewer, from, to Pos, battle *BattleResult) protocol.Battle {
+	return protocol.Battle{
+		From:     []int{from.X, from.Y},
+		To:       []int{to.X, to.Y},
+		Attacker: battle.AttackerType.String(),
+		Defender: battle.DefenderType.String(),
+		Result:   battle.Result,
+		Reveals:  r.battleReveals(viewer, battle),
+		Revealed: battle.Revealed,
+	}
+}
+
+func (r *Room) battleReveals(viewer Viewer, battle *BattleResult) []protocol.FlagReveal {
+	reveals := make([]protocol.FlagReveal, 0, len(battle.FlagReveals))
+	for _, reveal := range battle.FlagReveals {
+		flag := r.Pieces[reveal.PieceID]
+		if flag == nil || !r.seesExposed(viewer, flag) {
+			continue
+		}
+		reveals = append(reveals, protocol.FlagReveal{
+			ID:   reveal.PieceID,
+			Type: PieceFlag.String(),
+			Camp: reveal.Camp,
+			Pos:  []int{reveal.X, reveal.Y},
 		})
 	}
+	return reveals
+}
+
+func (r *Room) broadcastGameOver() {
+	r.broadcast(protocol.TypeGameOver, r.gameOverData())
 }
 
-func (r *Room) sendTo(userID, msgType string, data map[string]any) {
+func (r *Room) gameOverData() protocol.GameOver {
+	gameOver := protocol.GameOver{
+		Winner:   r.Winner,
+		WinnerID: r.WinnerID,
+		Reason:   r.Reason,
+	}
+	if r.Archive != nil && r.Log != nil {
+		gameOver.GameID = r.Log.GameID
+	}
+	return gameOver
+}
+
+func (r *Room) broadcastDrawOffer(msgType, offererID string) {
+	offerer, err := r.playerByID(offererID)
+	if err != nil {
+		return
+	}
+	r.broadcast(msgType, protocol.DrawOffer{From: offerer.Camp})
+}
+
+func (r *Room) sendTo(userID, msgType string, data any) {
 	player, err := r.playerByID(userID)
-	if err != nil || player.Conn == nil {
+	if err != nil {
+		return
+	}
+	r.send(player, msgType, data)
+}
+
+func (r *Room) send(player *Player, msgType string, data any) {
+	envelope := protocol.Envelope{Type: msgType, Data: data}
+	if player.recording {
+		player.replies = append(player.replies, envelope)
+	}
+	if player.Conn == nil {
 		return
 	}
-	_ = player.Conn.WriteJSON(map[string]any{
-		"type": msgType,
-		"data": data,
+	if err := player.Conn.WriteJSON(envelope); err != nil {
+		r.markOffline(player)
+	}
+}
+
+func (r *Room) sendStart(player *Player) {
+	r.sendTo(player.UserID, protocol.TypeStart, protocol.Start{
+		YouCamp: player.Camp,
+		Turn:    r.Turn,
 	})
+	r.sendTo(player.UserID, protocol.TypeSync, r.SyncData(PlayerViewer(player.UserID)))
 }
 
-func (r *Room) sendError(userID, msg string) {
-	r.sendTo(userID, "error", map[string]any{"msg": msg})
+func (r *Room) sendError(userID, action string, err error) {
+	r.sendTo(userID, protocol.TypeError, protocol.Error{
+		Code:   ErrorCode(err),
+		Msg:    err.Error(),
+		Action: action,
+	})
 }
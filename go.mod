@@ -0,0 +1,5 @@
+module github.com/m17604895278/GPT-Codex-Land-Chess-Go
+
+go 1.22
+
+require github.com/gorilla/websocket v1.5.3
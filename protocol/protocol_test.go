@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func intPtr(v int) *int { return &v }
+
+var goldenCases = []struct {
+	name  string
+	value any
+}{
+	{"message", Message{Type: TypeFlip, Data: json.RawMessage(`{"x":3,"y":5}`)}},
+	{"message_no_data", Message{Type: TypePing}},
+	{"flip", FlipPayload{X: 3, Y: 5}},
+	{"flip_seq", FlipPayload{X: 3, Y: 5, Seq: 7, ExpectedStep: intPtr(0)}},
+	{"move", MovePayload{FromX: 3, FromY: 5, ToX: 3, ToY: 6}},
+	{"move_seq", MovePayload{FromX: 3, FromY: 5, ToX: 3, ToY: 6, Seq: 42, ExpectedStep: intPtr(12)}},
+	{"seek", SeekPayload{Step: 12}},
+	{"play", PlayPayload{Speed: 2.5}},
+	{"envelope", Envelope{Type: TypePong, Data: Pong{TS: 1792211734}}},
+	{"hello", Hello{Version: 1, MinVersion: 1}},
+	{"start", Start{YouCamp: "unknown", Turn: "unknown"}},
+	{"camp_assigned", CampAssigned{YouCamp: "blue", Turn: "blue"}},
+	{"sync", Sync{
+		Board: [][]*CellView{
+			{{ID: "red-01", Flipped: true, Type: "司令", Camp: "red"}, nil},
+			{nil, {ID: "blue-25", Type: "军旗", Camp: "blue", Exposed: true}},
+		},
+		Turn: "blue",
+		Step: 12,
+		Clock: &Clock{
+			Turn:     "blue",
+			Deadline: 1792211734000,
+			Players: map[string]PlayerClock{
+				"red":  {RemainingMs: 280000, Overtimes: 2},
+				"blue": {RemainingMs: 295000, Overtimes: 3},
+			},
+		},
+	}},
+	{"sync_no_clock", Sync{Board: [][]*CellView{{{ID: "red-02"}}}, Turn: "red"}},
+	{"delta", Delta{
+		Step: 13,
+		Turn: "red",
+		Cells: []CellChange{
+			{Pos: []int{3, 5}},
+			{Pos: []int{3, 6}, Cell: &CellView{ID: "blue-07", Flipped: true, Type: "团长", Camp: "blue"}},
+		},
+		LastMove: &LastMove{Type: TypeMove, Camp: "blue", From: []int{3, 5}, To: []int{3, 6}},
+	}},
+	{"cell_change", CellChange{Pos: []int{0, 0}, Cell: &CellView{ID: "red-03"}}},
+	{"last_move_flip", LastMove{Type: TypeFlip, Camp: "red", From: []int{4, 5}}},
+	{"clock", Clock{Turn: "red", Players: map[string]PlayerClock{"red": {RemainingMs: 1000}}}},
+	{"player_clock", PlayerClock{RemainingMs: 295000, Overtimes: 3}},
+	{"cell_view", CellView{ID: "blue-07", Flipped: true, Type: "团长", Camp: "blue"}},
+	{"battle", Battle{
+		From:     []int{3, 5},
+		To:       []int{3, 6},
+		Attacker: "团长",
+		Defender: "营长",
+		Result:   "attacker_win",
+		Reveals:  []FlagReveal{{ID: "blue-25", Type: "军旗", Camp: "blue", Pos: []int{1, 0}}},
+		Revealed: true,
+	}},
+	{"battle_no_reveals", Battle{From: []int{0, 1}, To: []int{0, 2}, Attacker: "工兵", Defender: "地雷", Result: "attacker_win", Reveals: []FlagReveal{}}},
+	{"flag_reveal", FlagReveal{ID: "red-25", Type: "军旗", Camp: "red", Pos: []int{3, 11}}},
+	{"game_over", GameOver{Winner: "red", WinnerID: "alice", Reason: "flag_captured", GameID: "ae646d5a2b7c"}},
+	{"game_over_camps_unknown", GameOver{WinnerID: "bob", Reason: "resign"}},
+	{"game_over_draw", GameOver{Reason: "agreed_draw"}},
+	{"replay_info", ReplayInfo{GameID: "ae646d5a2b7c", Steps: 45, Winner: "blue", WinnerID: "bob", Reason: "resign"}},
+	{"error", Error{Code: "not_your_turn", Msg: "not your turn", Action: TypeMove}},
+	{"error_no_action", Error{Code: CodeUnsupportedVersion, Msg: "unsupported protocol version 0, server speaks 1-1"}},
+	{"draw_offer", DrawOffer{From: "red"}},
+	{"pong", Pong{TS: 1792211734}},
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+			path := filepath.Join("testdata", tc.name+".json")
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("encoding changed\ngot:\n%s\nwant:\n%s", got, want)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(tc.value))
+			if err := json.Unmarshal(want, decoded.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			again, err := json.Marshal(decoded.Elem().Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(append(again, '\n'), want) {
+				t.Fatalf("round trip changed the encoding\ngot:\n%s\nwant:\n%s", again, want)
+			}
+			if _, isEnvelope := tc.value.(Envelope); !isEnvelope && !reflect.DeepEqual(decoded.Elem().Interface(), tc.value) {
+				t.Fatalf("round trip = %#v, want %#v", decoded.Elem().Interface(), tc.value)
+			}
+		})
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	for v := MinVersion; v <= Version; v++ {
+		if err := CheckVersion(v); err != nil {
+			t.Errorf("CheckVersion(%d) = %v", v, err)
+		}
+	}
+	for _, v := range []int{0, MinVersion - 1, Version + 1} {
+		if err := CheckVersion(v); err == nil {
+			t.Errorf("CheckVersion(%d) accepted", v)
+		}
+	}
+}
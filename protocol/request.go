@@ -0,0 +1,52 @@
+package protocol
+
+import "encoding/json"
+
+const (
+	TypeFlip = "flip"
+	TypeMove = "move"
+	TypePing = "ping"
+
+	TypeResync = "resync"
+
+	TypeSeek  = "seek"
+	TypeNext  = "next"
+	TypePrev  = "prev"
+	TypePlay  = "play"
+	TypePause = "pause"
+
+	TypeSurrender   = "surrender"
+	TypeOfferDraw   = "offer_draw"
+	TypeAcceptDraw  = "accept_draw"
+	TypeDeclineDraw = "decline_draw"
+)
+
+type Message struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+type FlipPayload struct {
+	X            int   `json:"x"`
+	Y            int   `json:"y"`
+	Seq          int64 `json:"seq,omitempty"`
+	ExpectedStep *int  `json:"expectedStep,omitempty"`
+}
+
+type SeekPayload struct {
+	Step int `json:"step"`
+}
+
+type PlayPayload struct {
+	Speed float64 `json:"speed"`
+}
+
+type MovePayload struct {
+	FromX int `json:"fromX"`
+	FromY int `json:"fromY"`
+	ToX   int `json:"toX"`
+	ToY   int `json:"toY"`
+
+	Seq          int64 `json:"seq,omitempty"`
+	ExpectedStep *int  `json:"expectedStep,omitempty"`
+}
@@ -0,0 +1,130 @@
+package protocol
+
+const (
+	TypeHello        = "hello"
+	TypeStart        = "start"
+	TypeCampAssigned = "camp_assigned"
+	TypeSync         = "sync"
+	TypeDelta        = "delta"
+	TypeBattle       = "battle"
+	TypeGameOver     = "game_over"
+	TypeError        = "error"
+	TypePong         = "pong"
+	TypeDrawOffered  = "draw_offered"
+	TypeDrawDeclined = "draw_declined"
+	TypeReplayInfo   = "replay_info"
+)
+
+const CodeUnsupportedVersion = "unsupported_version"
+
+type Envelope struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type Hello struct {
+	Version    int `json:"version"`
+	MinVersion int `json:"minVersion"`
+}
+
+type Start struct {
+	YouCamp string `json:"youCamp"`
+	Turn    string `json:"turn"`
+}
+
+type CampAssigned struct {
+	YouCamp string `json:"youCamp"`
+	Turn    string `json:"turn"`
+}
+
+type Sync struct {
+	Board [][]*CellView `json:"board"`
+	Turn  string        `json:"turn"`
+	Step  int           `json:"step"`
+	Clock *Clock        `json:"clock,omitempty"`
+}
+
+type Delta struct {
+	Step     int          `json:"step"`
+	Turn     string       `json:"turn"`
+	Cells    []CellChange `json:"cells"`
+	LastMove *LastMove    `json:"lastMove,omitempty"`
+	Clock    *Clock       `json:"clock,omitempty"`
+}
+
+type CellChange struct {
+	Pos  []int     `json:"pos"`
+	Cell *CellView `json:"cell"`
+}
+
+type LastMove struct {
+	Type string `json:"type"`
+	Camp string `json:"camp"`
+	From []int  `json:"from"`
+	To   []int  `json:"to,omitempty"`
+}
+
+type Clock struct {
+	Turn     string                 `json:"turn"`
+	Deadline int64                  `json:"deadline,omitempty"`
+	Players  map[string]PlayerClock `json:"players"`
+}
+
+type PlayerClock struct {
+	RemainingMs int64 `json:"remainingMs"`
+	Overtimes   int   `json:"overtimes"`
+}
+
+type CellView struct {
+	ID      string `json:"id"`
+	Flipped bool   `json:"flipped"`
+	Type    string `json:"type,omitempty"`
+	Camp    string `json:"camp,omitempty"`
+	Exposed bool   `json:"exposed,omitempty"`
+}
+
+type Battle struct {
+	From     []int        `json:"from"`
+	To       []int        `json:"to"`
+	Attacker string       `json:"attacker"`
+	Defender string       `json:"defender"`
+	Result   string       `json:"result"`
+	Reveals  []FlagReveal `json:"reveals"`
+	Revealed bool         `json:"revealed"`
+}
+
+type FlagReveal struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Camp string `json:"camp"`
+	Pos  []int  `json:"pos"`
+}
+
+type GameOver struct {
+	Winner   string `json:"winner"`
+	WinnerID string `json:"winnerId,omitempty"`
+	Reason   string `json:"reason"`
+	GameID   string `json:"gameId,omitempty"`
+}
+
+type ReplayInfo struct {
+	GameID   string `json:"gameId"`
+	Steps    int    `json:"steps"`
+	Winner   string `json:"winner"`
+	WinnerID string `json:"winnerId,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+type Error struct {
+	Code   string `json:"code"`
+	Msg    string `json:"msg"`
+	Action string `json:"action,omitempty"`
+}
+
+type DrawOffer struct {
+	From string `json:"from"`
+}
+
+type Pong struct {
+	TS int64 `json:"ts"`
+}
@@ -0,0 +1,15 @@
+package protocol
+
+import "fmt"
+
+const (
+	Version    = 1
+	MinVersion = 1
+)
+
+func CheckVersion(v int) error {
+	if v < MinVersion || v > Version {
+		return fmt.Errorf("unsupported protocol version %d, server speaks %d-%d", v, MinVersion, Version)
+	}
+	return nil
+}